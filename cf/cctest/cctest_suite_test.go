@@ -0,0 +1,13 @@
+package cctest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCctest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cctest Suite")
+}
@@ -0,0 +1,72 @@
+package cctest
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var v3Filters = map[string]string{
+	"guids":              "guid",
+	"names":              "name",
+	"hosts":              "host",
+	"space_guids":        "space_guid",
+	"organization_guids": "organization_guid",
+}
+
+type filter struct {
+	field  string
+	values []string
+}
+
+func parseFilters(version string, query url.Values) ([]filter, error) {
+	var filters []filter
+
+	if version == "v3" {
+		for param, field := range v3Filters {
+			if value := query.Get(param); value != "" {
+				filters = append(filters, filter{field: field, values: strings.Split(value, ",")})
+			}
+		}
+		return filters, nil
+	}
+
+	for _, q := range query["q"] {
+		if parts := strings.SplitN(q, " IN ", 2); len(parts) == 2 {
+			filters = append(filters, filter{field: parts[0], values: strings.Split(parts[1], ",")})
+			continue
+		}
+
+		parts := strings.SplitN(q, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("The query parameter is invalid: %s", q)
+		}
+		filters = append(filters, filter{field: parts[0], values: []string{parts[1]}})
+	}
+
+	return filters, nil
+}
+
+func matchesFilters(resource *Resource, filters []filter) bool {
+	for _, filter := range filters {
+		value := resource.GUID
+		if filter.field != "guid" {
+			value = fmt.Sprint(resource.Entity[filter.field])
+		}
+
+		if !contains(filter.values, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
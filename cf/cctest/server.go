@@ -0,0 +1,645 @@
+package cctest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	Organizations    = "organizations"
+	Spaces           = "spaces"
+	Apps             = "apps"
+	Routes           = "routes"
+	ServiceInstances = "service_instances"
+)
+
+var parents = map[string]string{
+	Spaces:           Organizations,
+	Apps:             Spaces,
+	Routes:           Spaces,
+	ServiceInstances: Spaces,
+}
+
+var resourceNames = map[string]string{
+	Organizations:    "Organization",
+	Spaces:           "Space",
+	Apps:             "App",
+	Routes:           "Route",
+	ServiceInstances: "ServiceInstance",
+}
+
+type Resource struct {
+	GUID      string
+	Kind      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Entity    map[string]interface{}
+}
+
+// Fault is applied to the requests matching Method and Path, where empty
+// values match any method and Path is a prefix. Latency delays the response
+// and a zero StatusCode lets the request through afterwards. Times is the
+// number of requests affected, 0 keeps the fault until ClearFaults.
+type Fault struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+	Latency    time.Duration
+	Times      int
+}
+
+type Request struct {
+	Method string
+	Path   string
+	Token  string
+}
+
+type Server struct {
+	*httptest.Server
+
+	mutex     sync.Mutex
+	pageSize  int
+	resources map[string][]*Resource
+	tokens    map[string]bool
+	revoked   map[string]bool
+	faults    []*Fault
+	requests  []Request
+}
+
+func NewServer() *Server {
+	server := &Server{
+		pageSize:  50,
+		resources: map[string][]*Resource{},
+		tokens:    map[string]bool{},
+		revoked:   map[string]bool{},
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	return server
+}
+
+// SetPageSize sets the default page size of list responses.
+func (s *Server) SetPageSize(pageSize int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.pageSize = pageSize
+}
+
+// AddToken accepts token as a valid bearer token. Until a token is added the
+// server accepts any non-empty token that hasn't been revoked.
+func (s *Server) AddToken(token string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tokens[token] = true
+	delete(s.revoked, token)
+}
+
+// RevokeToken rejects token from now on.
+func (s *Server) RevokeToken(token string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.tokens, token)
+	s.revoked[token] = true
+}
+
+func (s *Server) InjectFault(fault Fault) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+func (s *Server) FailNext(statusCode int) {
+	s.InjectFault(Fault{StatusCode: statusCode, Times: 1})
+}
+
+func (s *Server) ClearFaults() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.faults = nil
+}
+
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) AddOrganization(name string) string {
+	return s.Add(Organizations, map[string]interface{}{"name": name})
+}
+
+func (s *Server) AddSpace(organizationGUID, name string) string {
+	return s.Add(Spaces, map[string]interface{}{"name": name, "organization_guid": organizationGUID})
+}
+
+func (s *Server) AddApp(spaceGUID, name string) string {
+	return s.Add(Apps, map[string]interface{}{"name": name, "space_guid": spaceGUID, "state": "STOPPED", "instances": 1, "memory": 1024})
+}
+
+func (s *Server) AddRoute(spaceGUID, host string) string {
+	return s.Add(Routes, map[string]interface{}{"host": host, "space_guid": spaceGUID, "path": ""})
+}
+
+func (s *Server) AddServiceInstance(spaceGUID, name string) string {
+	return s.Add(ServiceInstances, map[string]interface{}{"name": name, "space_guid": spaceGUID})
+}
+
+func (s *Server) Add(kind string, entity map[string]interface{}) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.add(kind, entity).GUID
+}
+
+// Get returns a copy of the resource, changing it doesn't affect the server.
+func (s *Server) Get(kind, guid string) (Resource, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	resource, ok := s.find(kind, guid)
+	if !ok {
+		return Resource{}, false
+	}
+	return resource.copy(), true
+}
+
+// All returns copies of the resources of the given kind.
+func (s *Server) All(kind string) []Resource {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	resources := []Resource{}
+	for _, resource := range s.resources[kind] {
+		resources = append(resources, resource.copy())
+	}
+	return resources
+}
+
+func (s *Server) add(kind string, entity map[string]interface{}) *Resource {
+	now := time.Now().UTC().Truncate(time.Second)
+	resource := &Resource{
+		GUID:      newGUID(),
+		Kind:      kind,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Entity:    copyValue(entity).(map[string]interface{}),
+	}
+	s.resources[kind] = append(s.resources[kind], resource)
+	return resource
+}
+
+func (r *Resource) copy() Resource {
+	resource := *r
+	resource.Entity = copyValue(r.Entity).(map[string]interface{})
+	return resource
+}
+
+func copyValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			copied[key] = copyValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, item := range value {
+			copied[i] = copyValue(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+func (s *Server) find(kind, guid string) (*Resource, bool) {
+	for _, resource := range s.resources[kind] {
+		if resource.GUID == guid {
+			return resource, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) remove(kind, guid string) {
+	resources := s.resources[kind]
+	for i, resource := range resources {
+		if resource.GUID == guid {
+			s.resources[kind] = append(resources[:i:i], resources[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	token := bearerToken(r)
+	fault := s.record(r, token)
+	if fault != nil {
+		select {
+		case <-time.After(fault.Latency):
+		case <-r.Context().Done():
+			return
+		}
+		if fault.StatusCode != 0 {
+			writeFault(w, r, fault)
+			return
+		}
+	}
+
+	if !s.authorized(token) {
+		writeError(w, r, http.StatusUnauthorized, 1000, "CF-InvalidAuthToken", "Invalid Auth Token")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 2 || (segments[0] != "v2" && segments[0] != "v3") {
+		writeError(w, r, http.StatusNotFound, 10000, "CF-NotFound", "Unknown request")
+		return
+	}
+
+	version := segments[0]
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch len(segments) {
+	case 2:
+		switch r.Method {
+		case "GET":
+			s.list(w, r, version, segments[1], nil)
+		case "POST":
+			s.create(w, r, version, segments[1])
+		default:
+			writeError(w, r, http.StatusMethodNotAllowed, 10000, "CF-NotFound", "Unknown request")
+		}
+	case 3:
+		switch r.Method {
+		case "GET":
+			s.show(w, r, version, segments[1], segments[2])
+		case "PUT", "PATCH":
+			s.update(w, r, version, segments[1], segments[2])
+		case "DELETE":
+			s.delete(w, r, version, segments[1], segments[2])
+		default:
+			writeError(w, r, http.StatusMethodNotAllowed, 10000, "CF-NotFound", "Unknown request")
+		}
+	case 4:
+		parent, ok := s.find(segments[1], segments[2])
+		if !ok || r.Method != "GET" || parents[segments[3]] != segments[1] {
+			s.notFound(w, r, segments[1], segments[2])
+			return
+		}
+		s.list(w, r, version, segments[3], parent)
+	default:
+		writeError(w, r, http.StatusNotFound, 10000, "CF-NotFound", "Unknown request")
+	}
+}
+
+func (s *Server) record(r *http.Request, token string) *Fault {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.RequestURI(), Token: token})
+
+	for i, fault := range s.faults {
+		if fault.Method != "" && fault.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, fault.Path) {
+			continue
+		}
+
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return fault
+	}
+
+	return nil
+}
+
+func (s *Server) authorized(token string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if token == "" || s.revoked[token] {
+		return false
+	}
+	if len(s.tokens) == 0 {
+		return true
+	}
+	return s.tokens[token]
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, version, kind string, parent *Resource) {
+	if _, ok := resourceNames[kind]; !ok {
+		writeError(w, r, http.StatusNotFound, 10000, "CF-NotFound", "Unknown request")
+		return
+	}
+
+	query := r.URL.Query()
+	filters, err := parseFilters(version, query)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, 10005, "CF-BadQueryParameter", err.Error())
+		return
+	}
+	if parent != nil {
+		filters = append(filters, filter{field: parentField(parent.Kind), values: []string{parent.GUID}})
+	}
+
+	var matches []*Resource
+	for _, resource := range s.resources[kind] {
+		if matchesFilters(resource, filters) {
+			matches = append(matches, resource)
+		}
+	}
+
+	pageSizeParam := "results-per-page"
+	if version == "v3" {
+		pageSizeParam = "per_page"
+	}
+	page := positiveInt(query.Get("page"), 1)
+	pageSize := positiveInt(query.Get(pageSizeParam), s.pageSize)
+
+	totalPages := (len(matches) + pageSize - 1) / pageSize
+	start := (page - 1) * pageSize
+	end := start + pageSize
+	if start > len(matches) {
+		start = len(matches)
+	}
+	if end > len(matches) {
+		end = len(matches)
+	}
+
+	resources := []interface{}{}
+	for _, resource := range matches[start:end] {
+		resources = append(resources, present(version, resource))
+	}
+
+	pageURL := func(number int) *string {
+		if number < 1 || number > totalPages {
+			return nil
+		}
+		query.Set("page", strconv.Itoa(number))
+		query.Set(pageSizeParam, strconv.Itoa(pageSize))
+		pageURL := r.URL.Path + "?" + query.Encode()
+		if version == "v3" {
+			pageURL = s.URL + pageURL
+		}
+		return &pageURL
+	}
+
+	if version == "v2" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"total_results": len(matches),
+			"total_pages":   totalPages,
+			"prev_url":      pageURL(page - 1),
+			"next_url":      pageURL(page + 1),
+			"resources":     resources,
+		})
+		return
+	}
+
+	link := func(number int) interface{} {
+		href := pageURL(number)
+		if href == nil {
+			return nil
+		}
+		return map[string]string{"href": *href}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pagination": map[string]interface{}{
+			"total_results": len(matches),
+			"total_pages":   totalPages,
+			"first":         link(1),
+			"last":          link(totalPages),
+			"next":          link(page + 1),
+			"previous":      link(page - 1),
+		},
+		"resources": resources,
+	})
+}
+
+func (s *Server) show(w http.ResponseWriter, r *http.Request, version, kind, guid string) {
+	resource, ok := s.find(kind, guid)
+	if !ok {
+		s.notFound(w, r, kind, guid)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, present(version, resource))
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, version, kind string) {
+	if _, ok := resourceNames[kind]; !ok {
+		writeError(w, r, http.StatusNotFound, 10000, "CF-NotFound", "Unknown request")
+		return
+	}
+
+	entity, ok := s.readEntity(w, r, kind)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, present(version, s.add(kind, entity)))
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, version, kind, guid string) {
+	resource, ok := s.find(kind, guid)
+	if !ok {
+		s.notFound(w, r, kind, guid)
+		return
+	}
+
+	entity, ok := s.readEntity(w, r, kind)
+	if !ok {
+		return
+	}
+
+	for key, value := range entity {
+		resource.Entity[key] = value
+	}
+	resource.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+
+	status := http.StatusCreated
+	if version == "v3" {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, present(version, resource))
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, version, kind, guid string) {
+	if _, ok := s.find(kind, guid); !ok {
+		s.notFound(w, r, kind, guid)
+		return
+	}
+
+	s.remove(kind, guid)
+	if version == "v3" {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) readEntity(w http.ResponseWriter, r *http.Request, kind string) (map[string]interface{}, bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, 1001, "CF-MessageParseError", "Request invalid due to parse error")
+		return nil, false
+	}
+
+	entity := map[string]interface{}{}
+	if len(body) > 0 {
+		err = json.Unmarshal(body, &entity)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, 1001, "CF-MessageParseError", "Request invalid due to parse error: "+err.Error())
+			return nil, false
+		}
+	}
+
+	if relationships, ok := entity["relationships"].(map[string]interface{}); ok {
+		for name, relationship := range relationships {
+			data, _ := relationship.(map[string]interface{})["data"].(map[string]interface{})
+			if guid, ok := data["guid"].(string); ok {
+				entity[name+"_guid"] = guid
+			}
+		}
+		delete(entity, "relationships")
+	}
+
+	if parentKind, ok := parents[kind]; ok {
+		if guid, ok := entity[parentField(parentKind)].(string); ok {
+			if _, exists := s.find(parentKind, guid); !exists {
+				writeError(w, r, http.StatusBadRequest, 1002, "CF-InvalidRelation", fmt.Sprintf("Invalid relation: %s %s not found", resourceNames[parentKind], guid))
+				return nil, false
+			}
+		}
+	}
+
+	return entity, true
+}
+
+func (s *Server) notFound(w http.ResponseWriter, r *http.Request, kind, guid string) {
+	name, ok := resourceNames[kind]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, 10000, "CF-NotFound", "Unknown request")
+		return
+	}
+
+	writeError(w, r, http.StatusNotFound, 10000, "CF-"+name+"NotFound", fmt.Sprintf("The %s could not be found: %s", strings.ToLower(name), guid))
+}
+
+func present(version string, resource *Resource) map[string]interface{} {
+	timestamp := func(t time.Time) string { return t.Format(time.RFC3339) }
+
+	if version == "v2" {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"guid":       resource.GUID,
+				"url":        "/v2/" + resource.Kind + "/" + resource.GUID,
+				"created_at": timestamp(resource.CreatedAt),
+				"updated_at": timestamp(resource.UpdatedAt),
+			},
+			"entity": resource.Entity,
+		}
+	}
+
+	presented := map[string]interface{}{}
+	relationships := map[string]interface{}{}
+	for key, value := range resource.Entity {
+		if strings.HasSuffix(key, "_guid") {
+			relationships[strings.TrimSuffix(key, "_guid")] = map[string]interface{}{
+				"data": map[string]interface{}{"guid": value},
+			}
+			continue
+		}
+		presented[key] = value
+	}
+
+	presented["guid"] = resource.GUID
+	presented["created_at"] = timestamp(resource.CreatedAt)
+	presented["updated_at"] = timestamp(resource.UpdatedAt)
+	presented["relationships"] = relationships
+	presented["links"] = map[string]interface{}{
+		"self": map[string]string{"href": "/v3/" + resource.Kind + "/" + resource.GUID},
+	}
+	return presented
+}
+
+func parentField(kind string) string {
+	return strings.TrimSuffix(kind, "s") + "_guid"
+}
+
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+func positiveInt(value string, fallback int) int {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return fallback
+	}
+	return number
+}
+
+func writeFault(w http.ResponseWriter, r *http.Request, fault *Fault) {
+	if fault.Body != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(fault.StatusCode)
+		w.Write([]byte(fault.Body))
+		return
+	}
+
+	switch fault.StatusCode {
+	case http.StatusUnauthorized:
+		writeError(w, r, fault.StatusCode, 1000, "CF-InvalidAuthToken", "Invalid Auth Token")
+	default:
+		writeError(w, r, fault.StatusCode, 10001, "UnknownError", "An unknown error occurred.")
+	}
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status, code int, errorCode, description string) {
+	if strings.HasPrefix(r.URL.Path, "/v3/") {
+		writeJSON(w, status, map[string]interface{}{
+			"errors": []map[string]interface{}{
+				{"code": code, "title": errorCode, "detail": description},
+			},
+		})
+		return
+	}
+
+	writeJSON(w, status, map[string]interface{}{
+		"code":        code,
+		"description": description,
+		"error_code":  errorCode,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func newGUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
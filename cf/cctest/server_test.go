@@ -0,0 +1,287 @@
+package cctest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/tscolari/cfapi/cf"
+	"github.com/tscolari/cfapi/cf/cctest"
+	"github.com/tscolari/cfapi/uaa"
+	uaafakes "github.com/tscolari/cfapi/uaa/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type v2Resource struct {
+	Metadata struct {
+		GUID string `json:"guid"`
+	} `json:"metadata"`
+	Entity struct {
+		Name             string `json:"name"`
+		SpaceGUID        string `json:"space_guid"`
+		OrganizationGUID string `json:"organization_guid"`
+	} `json:"entity"`
+}
+
+type v3Resource struct {
+	GUID          string `json:"guid"`
+	Name          string `json:"name"`
+	Relationships struct {
+		Space struct {
+			Data struct {
+				GUID string `json:"guid"`
+			} `json:"data"`
+		} `json:"space"`
+	} `json:"relationships"`
+}
+
+var _ = Describe("Server", func() {
+	var (
+		server    *cctest.Server
+		client    *cf.Client
+		orgGUID   string
+		spaceGUID string
+	)
+
+	BeforeEach(func() {
+		server = cctest.NewServer()
+		client = cf.NewClient(server.URL, "my-token")
+
+		orgGUID = server.AddOrganization("my-org")
+		spaceGUID = server.AddSpace(orgGUID, "dev")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	streamNames := func(path string) []string {
+		names := []string{}
+		err := client.Stream(path, func(data json.RawMessage) error {
+			var resource struct {
+				Name   string `json:"name"`
+				Entity struct {
+					Name string `json:"name"`
+				} `json:"entity"`
+			}
+			Expect(json.Unmarshal(data, &resource)).To(Succeed())
+			names = append(names, resource.Name+resource.Entity.Name)
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return names
+	}
+
+	Describe("v2", func() {
+		It("shows resources", func() {
+			var space v2Resource
+			err := client.Get("/v2/spaces/"+spaceGUID, &space)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(space.Metadata.GUID).To(Equal(spaceGUID))
+			Expect(space.Entity.Name).To(Equal("dev"))
+			Expect(space.Entity.OrganizationGUID).To(Equal(orgGUID))
+		})
+
+		It("creates, updates and deletes resources", func() {
+			var app v2Resource
+			err := client.Post("/v2/apps", map[string]string{"name": "my-app", "space_guid": spaceGUID}, &app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(app.Entity.Name).To(Equal("my-app"))
+
+			err = client.Put("/v2/apps/"+app.Metadata.GUID, map[string]string{"name": "renamed"}, &app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(app.Entity.Name).To(Equal("renamed"))
+			Expect(app.Entity.SpaceGUID).To(Equal(spaceGUID))
+
+			Expect(client.Delete("/v2/apps/"+app.Metadata.GUID, nil)).To(Succeed())
+			_, found := server.Get(cctest.Apps, app.Metadata.GUID)
+			Expect(found).To(BeFalse())
+		})
+
+		It("hands out copies of its resources", func() {
+			appGUID := server.AddApp(spaceGUID, "my-app")
+
+			app, found := server.Get(cctest.Apps, appGUID)
+			Expect(found).To(BeTrue())
+			app.Entity["name"] = "changed"
+			server.All(cctest.Apps)[0].Entity["name"] = "changed"
+
+			var shown v2Resource
+			Expect(client.Get("/v2/apps/"+appGUID, &shown)).To(Succeed())
+			Expect(shown.Entity.Name).To(Equal("my-app"))
+		})
+
+		It("returns not found errors", func() {
+			err := client.Get("/v2/apps/unknown", nil)
+			Expect(err).To(MatchError("Not Found"))
+		})
+
+		It("validates relationships", func() {
+			err := client.Post("/v2/apps", map[string]string{"name": "my-app", "space_guid": "unknown"}, nil)
+			Expect(err).To(MatchError("Bad Request"))
+		})
+
+		It("paginates and filters lists", func() {
+			server.SetPageSize(2)
+			otherSpace := server.AddSpace(orgGUID, "prod")
+			for _, name := range []string{"a", "b", "c"} {
+				server.AddApp(spaceGUID, name)
+			}
+			server.AddApp(otherSpace, "d")
+
+			Expect(streamNames("/v2/apps")).To(Equal([]string{"a", "b", "c", "d"}))
+			Expect(streamNames("/v2/apps?q=space_guid:" + spaceGUID)).To(Equal([]string{"a", "b", "c"}))
+			Expect(streamNames("/v2/apps?q=name%20IN%20a,d")).To(Equal([]string{"a", "d"}))
+			Expect(streamNames("/v2/spaces/" + otherSpace + "/apps")).To(Equal([]string{"d"}))
+
+			var page struct {
+				TotalResults int     `json:"total_results"`
+				TotalPages   int     `json:"total_pages"`
+				NextURL      *string `json:"next_url"`
+			}
+			Expect(client.Get("/v2/apps?results-per-page=3&page=2", &page)).To(Succeed())
+			Expect(page.TotalResults).To(Equal(4))
+			Expect(page.TotalPages).To(Equal(2))
+			Expect(page.NextURL).To(BeNil())
+		})
+	})
+
+	Describe("v3", func() {
+		It("shows resources with relationships", func() {
+			appGUID := server.AddApp(spaceGUID, "my-app")
+
+			var app v3Resource
+			Expect(client.Get("/v3/apps/"+appGUID, &app)).To(Succeed())
+			Expect(app.GUID).To(Equal(appGUID))
+			Expect(app.Name).To(Equal("my-app"))
+			Expect(app.Relationships.Space.Data.GUID).To(Equal(spaceGUID))
+		})
+
+		It("creates, updates and deletes resources", func() {
+			var instance v3Resource
+			err := client.Post("/v3/service_instances", map[string]string{"name": "db", "space_guid": spaceGUID}, &instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instance.Relationships.Space.Data.GUID).To(Equal(spaceGUID))
+
+			err = client.Put("/v3/service_instances/"+instance.GUID, map[string]string{"name": "cache"}, &instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instance.Name).To(Equal("cache"))
+
+			Expect(client.Delete("/v3/service_instances/"+instance.GUID, nil)).To(Succeed())
+			Expect(server.All(cctest.ServiceInstances)).To(BeEmpty())
+		})
+
+		It("paginates and filters lists", func() {
+			server.SetPageSize(1)
+			server.AddRoute(spaceGUID, "www")
+			server.AddRoute(spaceGUID, "api")
+
+			Expect(streamNames("/v3/spaces")).To(Equal([]string{"dev"}))
+			Expect(streamNames("/v3/organizations?names=my-org,other")).To(Equal([]string{"my-org"}))
+
+			hosts := []string{}
+			err := client.Stream("/v3/routes?space_guids="+spaceGUID, func(data json.RawMessage) error {
+				var route struct {
+					Host string `json:"host"`
+				}
+				Expect(json.Unmarshal(data, &route)).To(Succeed())
+				hosts = append(hosts, route.Host)
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hosts).To(Equal([]string{"www", "api"}))
+		})
+	})
+
+	Describe("authentication", func() {
+		It("rejects requests without a token", func() {
+			client = cf.NewClient(server.URL, "")
+			Expect(client.Get("/v2/organizations", nil)).To(MatchError("Unauthorized"))
+		})
+
+		It("only accepts registered tokens once any is added", func() {
+			server.AddToken("valid-token")
+			Expect(client.Get("/v2/organizations", nil)).To(MatchError("Unauthorized"))
+
+			client = cf.NewClient(server.URL, "valid-token")
+			Expect(client.Get("/v2/organizations", nil)).To(Succeed())
+
+			server.RevokeToken("valid-token")
+			Expect(client.Get("/v2/organizations", nil)).To(MatchError("Unauthorized"))
+		})
+
+		It("keeps accepting other tokens when a token is revoked before any is added", func() {
+			server.RevokeToken("revoked-token")
+			Expect(client.Get("/v2/organizations", nil)).To(Succeed())
+
+			client = cf.NewClient(server.URL, "revoked-token")
+			Expect(client.Get("/v2/organizations", nil)).To(MatchError("Unauthorized"))
+		})
+
+		It("lets a RefresherClient recover from revoked tokens", func() {
+			server.AddToken("new-token")
+			refresher := new(uaafakes.FakeRefresher)
+			refresher.RefreshTokenReturns(&uaa.Tokens{AccessToken: "new-token"}, nil)
+
+			refresherClient := cf.NewRefresherClient(server.URL, uaa.Tokens{AccessToken: "old-token", RefreshToken: "refresh"}, refresher)
+			Expect(refresherClient.Get("/v2/organizations", nil)).To(Succeed())
+
+			requests := server.Requests()
+			Expect(requests).To(HaveLen(2))
+			Expect(requests[0].Token).To(Equal("old-token"))
+			Expect(requests[1].Token).To(Equal("new-token"))
+		})
+	})
+
+	Describe("faults", func() {
+		It("fails the next request", func() {
+			server.FailNext(http.StatusInternalServerError)
+
+			Expect(client.Get("/v2/organizations", nil)).To(MatchError("An unknown error occurred."))
+			Expect(client.Get("/v2/organizations", nil)).To(Succeed())
+		})
+
+		It("injects faults matching the method and path", func() {
+			server.InjectFault(cctest.Fault{Method: "GET", Path: "/v2/apps", StatusCode: http.StatusBadGateway, Body: `{"description":"gateway down"}`, Times: 2})
+
+			Expect(client.Get("/v2/spaces", nil)).To(Succeed())
+			Expect(client.Get("/v2/apps", nil)).To(MatchError("gateway down"))
+			Expect(client.Get("/v2/apps", nil)).To(MatchError("gateway down"))
+			Expect(client.Get("/v2/apps", nil)).To(Succeed())
+		})
+
+		It("injects unauthorized responses", func() {
+			server.InjectFault(cctest.Fault{StatusCode: http.StatusUnauthorized})
+			Expect(client.Get("/v2/apps", nil)).To(MatchError("Unauthorized"))
+
+			server.ClearFaults()
+			Expect(client.Get("/v2/apps", nil)).To(Succeed())
+		})
+
+		It("adds latency", func() {
+			server.InjectFault(cctest.Fault{Latency: 50 * time.Millisecond, Times: 1})
+
+			start := time.Now()
+			Expect(client.Get("/v2/apps", nil)).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+		})
+
+		It("stops waiting when the request is cancelled", func() {
+			server.InjectFault(cctest.Fault{Latency: time.Hour, Times: 1})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			Expect(client.GetWithContext(ctx, "/v2/apps", nil)).ToNot(Succeed())
+
+			closed := make(chan struct{})
+			go func() {
+				server.Close()
+				close(closed)
+			}()
+			Eventually(closed).Should(BeClosed())
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package cf
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,14 +9,25 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/tscolari/cfapi/metrics"
+	"github.com/tscolari/cfapi/telemetry"
+	"github.com/tscolari/cfapi/trace"
 	"github.com/tscolari/cfapi/uaa"
 )
 
+type responseHandler func(resp *http.Response) error
+
 type Client struct {
-	accessToken string
-	endpoint    string
-	client      *http.Client
+	tokenMutex   sync.RWMutex
+	accessToken  string
+	endpoint     string
+	client       *http.Client
+	TracePrinter trace.Printer
+	Metrics      metrics.Collector
+	Tracer       telemetry.Tracer
 }
 
 func NewClient(endpoint, accessToken string) *Client {
@@ -27,29 +39,62 @@ func NewClient(endpoint, accessToken string) *Client {
 }
 
 func (c *Client) Get(path string, response interface{}) error {
-	return c.fetch("GET", path, nil, response)
+	return c.GetWithContext(context.Background(), path, response)
 }
 
 func (c *Client) Put(path string, options map[string]string, response interface{}) error {
-	return c.fetch("PUT", path, options, response)
+	return c.PutWithContext(context.Background(), path, options, response)
 }
 
 func (c *Client) Post(path string, options map[string]string, response interface{}) error {
-	return c.fetch("POST", path, options, response)
+	return c.PostWithContext(context.Background(), path, options, response)
 }
 
 func (c *Client) Delete(path string, options map[string]string) error {
-	return c.fetch("DELETE", path, options, nil)
+	return c.DeleteWithContext(context.Background(), path, options)
+}
+
+func (c *Client) GetWithContext(ctx context.Context, path string, response interface{}) error {
+	return c.fetch(ctx, "GET", path, nil, response)
+}
+
+func (c *Client) PutWithContext(ctx context.Context, path string, options map[string]string, response interface{}) error {
+	return c.fetch(ctx, "PUT", path, options, response)
+}
+
+func (c *Client) PostWithContext(ctx context.Context, path string, options map[string]string, response interface{}) error {
+	return c.fetch(ctx, "POST", path, options, response)
+}
+
+func (c *Client) DeleteWithContext(ctx context.Context, path string, options map[string]string) error {
+	return c.fetch(ctx, "DELETE", path, options, nil)
 }
 
 func (c *Client) CurrentTokens() uaa.Tokens {
 	return uaa.Tokens{
-		AccessToken: c.accessToken,
+		AccessToken: c.currentAccessToken(),
 	}
 }
 
-func (c *Client) fetch(method, path string, options map[string]string, response interface{}) error {
-	req, err := c.createRequest(method, path, options)
+func (c *Client) currentAccessToken() string {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+	return c.accessToken
+}
+
+func (c *Client) fetch(ctx context.Context, method, path string, options map[string]string, response interface{}) error {
+	return c.do(ctx, method, path, options, func(resp *http.Response) error {
+		return c.parseResponse(resp, response)
+	})
+}
+
+func (c *Client) do(ctx context.Context, method, path string, options map[string]string, handle responseHandler) (err error) {
+	ctx, span := telemetry.StartSpan(c.Tracer, ctx, "cf "+method+" "+metrics.PathTemplate(path))
+	defer func() { telemetry.EndSpan(span, err) }()
+	span.SetAttribute("http.method", method)
+	span.SetAttribute("http.url", c.endpoint+path)
+
+	req, err := c.createRequest(ctx, method, path, options)
 	if err != nil {
 		return err
 	}
@@ -58,10 +103,11 @@ func (c *Client) fetch(method, path string, options map[string]string, response
 		return err
 	}
 
-	return c.parseResponse(resp, response)
+	span.SetAttribute("http.status_code", resp.StatusCode)
+	return handle(resp)
 }
 
-func (c *Client) createRequest(method, path string, body map[string]string) (*http.Request, error) {
+func (c *Client) createRequest(ctx context.Context, method, path string, body map[string]string) (*http.Request, error) {
 	var requestBody io.Reader
 
 	if body != nil {
@@ -77,39 +123,46 @@ func (c *Client) createRequest(method, path string, body map[string]string) (*ht
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "bearer "+c.accessToken)
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "bearer "+c.currentAccessToken())
 	req.Header.Set("Content-Type", "application/json")
+	telemetry.Inject(c.Tracer, ctx, req.Header)
 	return req, err
 }
 
 func (c *Client) executeRequest(request *http.Request) (*http.Response, error) {
+	trace.DumpRequest(c.TracePrinter, request)
+	start := time.Now()
 	resp, err := c.client.Do(request)
 	if err != nil {
+		c.observeRequest(request, 0, start)
 		return nil, fmt.Errorf("Failed to connect: %s", err.Error())
 	}
 
+	c.observeRequest(request, resp.StatusCode, start)
+	trace.DumpResponse(c.TracePrinter, resp)
+
 	return resp, nil
 }
 
+func (c *Client) observeRequest(request *http.Request, statusCode int, start time.Time) {
+	if c.Metrics == nil {
+		return
+	}
+
+	c.Metrics.ObserveRequest(request.Method, request.URL.Path, statusCode, time.Since(start))
+}
+
 func (c *Client) parseResponse(resp *http.Response, returnObj interface{}) error {
 	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	err := c.checkResponse(resp)
 	if err != nil {
 		return err
 	}
 
-	if resp.StatusCode >= 500 {
-		var errResp Error
-		err = json.Unmarshal(body, &errResp)
-		if err != nil {
-			return fmt.Errorf("%s: %s", http.StatusText(resp.StatusCode), body)
-		}
-
-		return errors.New(strings.TrimSpace(errResp.Description))
-	}
-
-	if resp.StatusCode >= 400 {
-		return errors.New(http.StatusText(resp.StatusCode))
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
 	}
 
 	if returnObj == nil {
@@ -123,3 +176,26 @@ func (c *Client) parseResponse(resp *http.Response, returnObj interface{}) error
 
 	return nil
 }
+
+func (c *Client) checkResponse(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 500 {
+		var errResp Error
+		err = json.Unmarshal(body, &errResp)
+		if err != nil {
+			return fmt.Errorf("%s: %s", http.StatusText(resp.StatusCode), body)
+		}
+
+		return errors.New(strings.TrimSpace(errResp.Description))
+	}
+
+	return errors.New(http.StatusText(resp.StatusCode))
+}
@@ -1,6 +1,8 @@
 package cf_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,6 +10,10 @@ import (
 
 	"github.com/cloudfoundry/cli/cf/api/resources"
 	"github.com/tscolari/cfapi/cf"
+	"github.com/tscolari/cfapi/metrics"
+	"github.com/tscolari/cfapi/telemetry"
+	telemetryfakes "github.com/tscolari/cfapi/telemetry/fakes"
+	"github.com/tscolari/cfapi/trace"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -49,6 +55,110 @@ var _ = Describe("Client", func() {
 			Expect(*response.Entity.Memory).To(BeEquivalentTo(1024))
 		})
 
+		Context("when a TracePrinter is set", func() {
+			It("dumps the request and response without the access token", func() {
+				output := new(bytes.Buffer)
+				client.TracePrinter = trace.NewWriterPrinter(output)
+
+				err := client.Get("/app/123", &response)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(output.String()).To(ContainSubstring("GET /app/123 HTTP/1.1"))
+				Expect(output.String()).To(ContainSubstring("Authorization: bearer [PRIVATE DATA HIDDEN]"))
+				Expect(output.String()).To(ContainSubstring("HTTP/1.1 200 OK"))
+				Expect(output.String()).To(ContainSubstring("49934910-756a-46c5-bae1-b82540e28937"))
+				Expect(output.String()).ToNot(ContainSubstring("my-access-token"))
+			})
+		})
+
+		Context("when Metrics is set", func() {
+			It("records the request by method, path template and status code", func() {
+				collector := metrics.NewPrometheusCollector("")
+				client.Metrics = collector
+
+				err := client.Get("/v2/apps/49934910-756a-46c5-bae1-b82540e28937", &response)
+				Expect(err).ToNot(HaveOccurred())
+
+				output := new(bytes.Buffer)
+				collector.WriteTo(output)
+				Expect(output.String()).To(ContainSubstring(`cfapi_requests_total{method="GET",path="/v2/apps/:guid",code="200"} 1`))
+				Expect(output.String()).To(ContainSubstring(`cfapi_request_duration_seconds_count{method="GET",path="/v2/apps/:guid"} 1`))
+			})
+		})
+
+		Context("when a Tracer is set", func() {
+			var (
+				tracer *telemetryfakes.FakeTracer
+				span   *telemetryfakes.FakeSpan
+			)
+
+			BeforeEach(func() {
+				tracer = new(telemetryfakes.FakeTracer)
+				span = new(telemetryfakes.FakeSpan)
+				tracer.StartStub = func(ctx context.Context, name string) (context.Context, telemetry.Span) {
+					return ctx, span
+				}
+				tracer.InjectStub = func(ctx context.Context, header http.Header) {
+					header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+				}
+
+				handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.Header.Get("traceparent")).To(Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+					w.Header().Set("Content-Type", "application/json")
+					w.Write(readResponseJSON("app-response.json"))
+				})
+			})
+
+			JustBeforeEach(func() {
+				client.Tracer = tracer
+			})
+
+			It("wraps the request in a span and propagates the trace context", func() {
+				ctx := context.WithValue(context.Background(), "key", "value")
+				err := client.GetWithContext(ctx, "/v2/apps/49934910-756a-46c5-bae1-b82540e28937", &response)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(tracer.StartCallCount()).To(Equal(1))
+				parentCtx, name := tracer.StartArgsForCall(0)
+				Expect(parentCtx).To(Equal(ctx))
+				Expect(name).To(Equal("cf GET /v2/apps/:guid"))
+				Expect(tracer.InjectCallCount()).To(Equal(1))
+
+				key, value := span.SetAttributeArgsForCall(span.SetAttributeCallCount() - 1)
+				Expect(key).To(Equal("http.status_code"))
+				Expect(value).To(Equal(200))
+				Expect(span.RecordErrorCallCount()).To(Equal(0))
+				Expect(span.EndCallCount()).To(Equal(1))
+			})
+
+			Context("when the request fails", func() {
+				BeforeEach(func() {
+					handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						http.Error(w, ``, http.StatusUnauthorized)
+					})
+				})
+
+				It("records the error in the span", func() {
+					err := client.Get("/app/123", &response)
+					Expect(err).To(MatchError("Unauthorized"))
+
+					Expect(span.RecordErrorCallCount()).To(Equal(1))
+					Expect(span.RecordErrorArgsForCall(0)).To(MatchError("Unauthorized"))
+					Expect(span.EndCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		Context("when the context is cancelled", func() {
+			It("returns an error", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				err := client.GetWithContext(ctx, "/app/123", &response)
+				Expect(err.Error()).To(ContainSubstring("Failed to connect"))
+			})
+		})
+
 		Context("when something goes wrong", func() {
 			Context("when cloudcontroller returns an error", func() {
 				BeforeEach(func() {
@@ -0,0 +1,37 @@
+package cf
+
+import (
+	"context"
+	"io"
+
+	"github.com/tscolari/cfapi/uaa"
+)
+
+//go:generate counterfeiter -o fakes/fake_cloud_controller.go . CloudController
+
+type CloudController interface {
+	Get(path string, response interface{}) error
+	Put(path string, options map[string]string, response interface{}) error
+	Post(path string, options map[string]string, response interface{}) error
+	Delete(path string, options map[string]string) error
+
+	GetWithContext(ctx context.Context, path string, response interface{}) error
+	PutWithContext(ctx context.Context, path string, options map[string]string, response interface{}) error
+	PostWithContext(ctx context.Context, path string, options map[string]string, response interface{}) error
+	DeleteWithContext(ctx context.Context, path string, options map[string]string) error
+
+	Stream(path string, fn ResourceFunc) error
+	StreamWithContext(ctx context.Context, path string, fn ResourceFunc) error
+	GetRaw(path string) (io.ReadCloser, error)
+	GetRawWithContext(ctx context.Context, path string) (io.ReadCloser, error)
+
+	CurrentUser(userInfo uaa.UserInfoFetcher) (*CurrentUser, error)
+	CurrentUserWithContext(ctx context.Context, userInfo uaa.UserInfoFetcher) (*CurrentUser, error)
+
+	CurrentTokens() uaa.Tokens
+}
+
+var (
+	_ CloudController = (*Client)(nil)
+	_ CloudController = (*RefresherClient)(nil)
+)
@@ -0,0 +1,190 @@
+package cf
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/tscolari/cfapi/uaa"
+)
+
+type CurrentUser struct {
+	uaa.UserInfo
+	GUID             string
+	Admin            bool
+	Active           bool
+	DefaultSpaceGUID string
+	Organizations    []OrganizationRoles
+	Spaces           []SpaceRoles
+}
+
+type OrganizationRoles struct {
+	GUID  string
+	Name  string
+	Roles []string
+}
+
+type SpaceRoles struct {
+	GUID             string
+	Name             string
+	OrganizationGUID string
+	Roles            []string
+}
+
+var organizationRolePaths = []struct {
+	role string
+	path string
+}{
+	{"user", "organizations"},
+	{"manager", "managed_organizations"},
+	{"billing_manager", "billing_managed_organizations"},
+	{"auditor", "audited_organizations"},
+}
+
+var spaceRolePaths = []struct {
+	role string
+	path string
+}{
+	{"developer", "spaces"},
+	{"manager", "managed_spaces"},
+	{"auditor", "audited_spaces"},
+}
+
+type userResource struct {
+	Metadata struct {
+		GUID string `json:"guid"`
+	} `json:"metadata"`
+	Entity struct {
+		Admin            bool    `json:"admin"`
+		Active           bool    `json:"active"`
+		DefaultSpaceGUID *string `json:"default_space_guid"`
+	} `json:"entity"`
+}
+
+type namedResource struct {
+	Metadata struct {
+		GUID string `json:"guid"`
+	} `json:"metadata"`
+	Entity struct {
+		Name             string `json:"name"`
+		OrganizationGUID string `json:"organization_guid"`
+	} `json:"entity"`
+}
+
+func (c *Client) CurrentUser(userInfo uaa.UserInfoFetcher) (*CurrentUser, error) {
+	return c.CurrentUserWithContext(context.Background(), userInfo)
+}
+
+func (c *Client) CurrentUserWithContext(ctx context.Context, userInfo uaa.UserInfoFetcher) (*CurrentUser, error) {
+	info, err := userInfo.UserInfoWithContext(ctx, c.currentAccessToken())
+	if err != nil {
+		return nil, err
+	}
+
+	return c.currentUser(ctx, c.do, info)
+}
+
+func (c *RefresherClient) CurrentUser(userInfo uaa.UserInfoFetcher) (*CurrentUser, error) {
+	return c.CurrentUserWithContext(context.Background(), userInfo)
+}
+
+func (c *RefresherClient) CurrentUserWithContext(ctx context.Context, userInfo uaa.UserInfoFetcher) (*CurrentUser, error) {
+	err := c.syncTokenSource()
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken := c.currentAccessToken()
+	info, err := userInfo.UserInfoWithContext(ctx, accessToken)
+	if uaaErr, ok := err.(*uaa.Error); ok && uaaErr.StatusCode == http.StatusUnauthorized {
+		refreshErr := c.refreshTokens(ctx)
+		if refreshErr != nil {
+			return nil, refreshErr
+		}
+		if c.currentAccessToken() != accessToken {
+			info, err = userInfo.UserInfoWithContext(ctx, c.currentAccessToken())
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.currentUser(ctx, c.do, info)
+}
+
+func (c *Client) currentUser(ctx context.Context, do requestFunc, info *uaa.UserInfo) (*CurrentUser, error) {
+	user := &CurrentUser{UserInfo: *info}
+
+	var resource userResource
+	err := do(ctx, "GET", "/v2/users/"+url.PathEscape(info.ID()), nil, func(resp *http.Response) error {
+		return c.parseResponse(resp, &resource)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.GUID = resource.Metadata.GUID
+	user.Admin = resource.Entity.Admin
+	user.Active = resource.Entity.Active
+	if resource.Entity.DefaultSpaceGUID != nil {
+		user.DefaultSpaceGUID = *resource.Entity.DefaultSpaceGUID
+	}
+
+	organizations := map[string]int{}
+	for _, rolePath := range organizationRolePaths {
+		role := rolePath.role
+		err = c.stream(ctx, do, "/v2/users/"+user.GUID+"/"+rolePath.path, func(data json.RawMessage) error {
+			var org namedResource
+			err := json.Unmarshal(data, &org)
+			if err != nil {
+				return err
+			}
+
+			index, ok := organizations[org.Metadata.GUID]
+			if !ok {
+				index = len(user.Organizations)
+				organizations[org.Metadata.GUID] = index
+				user.Organizations = append(user.Organizations, OrganizationRoles{
+					GUID: org.Metadata.GUID,
+					Name: org.Entity.Name,
+				})
+			}
+			user.Organizations[index].Roles = append(user.Organizations[index].Roles, role)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	spaces := map[string]int{}
+	for _, rolePath := range spaceRolePaths {
+		role := rolePath.role
+		err = c.stream(ctx, do, "/v2/users/"+user.GUID+"/"+rolePath.path, func(data json.RawMessage) error {
+			var space namedResource
+			err := json.Unmarshal(data, &space)
+			if err != nil {
+				return err
+			}
+
+			index, ok := spaces[space.Metadata.GUID]
+			if !ok {
+				index = len(user.Spaces)
+				spaces[space.Metadata.GUID] = index
+				user.Spaces = append(user.Spaces, SpaceRoles{
+					GUID:             space.Metadata.GUID,
+					Name:             space.Entity.Name,
+					OrganizationGUID: space.Entity.OrganizationGUID,
+				})
+			}
+			user.Spaces[index].Roles = append(user.Spaces[index].Roles, role)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
@@ -0,0 +1,122 @@
+package cf_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tscolari/cfapi/cf"
+	"github.com/tscolari/cfapi/uaa"
+	uaafakes "github.com/tscolari/cfapi/uaa/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeUserInfoFetcher struct {
+	tokens []string
+	infos  []*uaa.UserInfo
+	errs   []error
+}
+
+func (f *fakeUserInfoFetcher) UserInfoWithContext(ctx context.Context, accessToken string) (*uaa.UserInfo, error) {
+	call := len(f.tokens)
+	f.tokens = append(f.tokens, accessToken)
+	return f.infos[call], f.errs[call]
+}
+
+var _ = Describe("CurrentUser", func() {
+	var server *httptest.Server
+	var userInfo *fakeUserInfoFetcher
+	var info *uaa.UserInfo
+
+	BeforeEach(func() {
+		info = &uaa.UserInfo{UserID: "user-guid", UserName: "marissa", Email: "marissa@example.com"}
+		userInfo = &fakeUserInfoFetcher{infos: []*uaa.UserInfo{info}, errs: []error{nil}}
+
+		pages := map[string]string{
+			"/v2/users/user-guid": `{"metadata":{"guid":"user-guid"},"entity":{"admin":false,"active":true,"default_space_guid":"space-1"}}`,
+			"/v2/users/user-guid/organizations": `{"next_url":"/v2/users/user-guid/organizations?page=2","resources":[
+				{"metadata":{"guid":"org-1"},"entity":{"name":"first-org"}}]}`,
+			"/v2/users/user-guid/organizations?page=2": `{"next_url":null,"resources":[
+				{"metadata":{"guid":"org-2"},"entity":{"name":"second-org"}}]}`,
+			"/v2/users/user-guid/managed_organizations":         `{"resources":[{"metadata":{"guid":"org-1"},"entity":{"name":"first-org"}}]}`,
+			"/v2/users/user-guid/billing_managed_organizations": `{"resources":[]}`,
+			"/v2/users/user-guid/audited_organizations":         `{"resources":[{"metadata":{"guid":"org-2"},"entity":{"name":"second-org"}}]}`,
+			"/v2/users/user-guid/spaces": `{"resources":[
+				{"metadata":{"guid":"space-1"},"entity":{"name":"dev","organization_guid":"org-1"}}]}`,
+			"/v2/users/user-guid/managed_spaces": `{"resources":[
+				{"metadata":{"guid":"space-2"},"entity":{"name":"prod","organization_guid":"org-1"}}]}`,
+			"/v2/users/user-guid/audited_spaces": `{"resources":[
+				{"metadata":{"guid":"space-1"},"entity":{"name":"dev","organization_guid":"org-1"}}]}`,
+		}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "bearer expired-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			page, ok := pages[r.URL.RequestURI()]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(page))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("combines the uaa user info with the cloud controller user and roles", func() {
+		client := cf.NewClient(server.URL, "my-token")
+
+		user, err := client.CurrentUser(userInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(userInfo.tokens).To(Equal([]string{"my-token"}))
+
+		Expect(user.UserName).To(Equal("marissa"))
+		Expect(user.Email).To(Equal("marissa@example.com"))
+		Expect(user.GUID).To(Equal("user-guid"))
+		Expect(user.Active).To(BeTrue())
+		Expect(user.Admin).To(BeFalse())
+		Expect(user.DefaultSpaceGUID).To(Equal("space-1"))
+
+		Expect(user.Organizations).To(Equal([]cf.OrganizationRoles{
+			{GUID: "org-1", Name: "first-org", Roles: []string{"user", "manager"}},
+			{GUID: "org-2", Name: "second-org", Roles: []string{"user", "auditor"}},
+		}))
+		Expect(user.Spaces).To(Equal([]cf.SpaceRoles{
+			{GUID: "space-1", Name: "dev", OrganizationGUID: "org-1", Roles: []string{"developer", "auditor"}},
+			{GUID: "space-2", Name: "prod", OrganizationGUID: "org-1", Roles: []string{"manager"}},
+		}))
+	})
+
+	It("returns cloud controller errors", func() {
+		info.UserID = "unknown-guid"
+		client := cf.NewClient(server.URL, "my-token")
+
+		_, err := client.CurrentUser(userInfo)
+		Expect(err).To(MatchError("Not Found"))
+	})
+
+	Context("with a RefresherClient", func() {
+		It("refreshes the tokens when uaa rejects the access token", func() {
+			userInfo.infos = []*uaa.UserInfo{nil, info}
+			userInfo.errs = []error{&uaa.Error{StatusCode: http.StatusUnauthorized, ErrorCode: "invalid_token"}, nil}
+
+			refresher := new(uaafakes.FakeRefresher)
+			refresher.RefreshTokenReturns(&uaa.Tokens{AccessToken: "new-token", RefreshToken: "new-refresh-token"}, nil)
+			client := cf.NewRefresherClient(server.URL, uaa.Tokens{AccessToken: "expired-token", RefreshToken: "refresh-token"}, refresher)
+
+			user, err := client.CurrentUser(userInfo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.GUID).To(Equal("user-guid"))
+
+			Expect(userInfo.tokens).To(Equal([]string{"expired-token", "new-token"}))
+			Expect(refresher.RefreshTokenCallCount()).To(Equal(1))
+		})
+	})
+})
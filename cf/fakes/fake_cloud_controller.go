@@ -0,0 +1,1203 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/tscolari/cfapi/cf"
+	"github.com/tscolari/cfapi/uaa"
+)
+
+type FakeCloudController struct {
+	CurrentTokensStub        func() uaa.Tokens
+	currentTokensMutex       sync.RWMutex
+	currentTokensArgsForCall []struct {
+	}
+	currentTokensReturns struct {
+		result1 uaa.Tokens
+	}
+	currentTokensReturnsOnCall map[int]struct {
+		result1 uaa.Tokens
+	}
+	CurrentUserStub        func(uaa.UserInfoFetcher) (*cf.CurrentUser, error)
+	currentUserMutex       sync.RWMutex
+	currentUserArgsForCall []struct {
+		arg1 uaa.UserInfoFetcher
+	}
+	currentUserReturns struct {
+		result1 *cf.CurrentUser
+		result2 error
+	}
+	currentUserReturnsOnCall map[int]struct {
+		result1 *cf.CurrentUser
+		result2 error
+	}
+	CurrentUserWithContextStub        func(context.Context, uaa.UserInfoFetcher) (*cf.CurrentUser, error)
+	currentUserWithContextMutex       sync.RWMutex
+	currentUserWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 uaa.UserInfoFetcher
+	}
+	currentUserWithContextReturns struct {
+		result1 *cf.CurrentUser
+		result2 error
+	}
+	currentUserWithContextReturnsOnCall map[int]struct {
+		result1 *cf.CurrentUser
+		result2 error
+	}
+	DeleteStub        func(string, map[string]string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 string
+		arg2 map[string]string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteWithContextStub        func(context.Context, string, map[string]string) error
+	deleteWithContextMutex       sync.RWMutex
+	deleteWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]string
+	}
+	deleteWithContextReturns struct {
+		result1 error
+	}
+	deleteWithContextReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetStub        func(string, interface{}) error
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 string
+		arg2 interface{}
+	}
+	getReturns struct {
+		result1 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetRawStub        func(string) (io.ReadCloser, error)
+	getRawMutex       sync.RWMutex
+	getRawArgsForCall []struct {
+		arg1 string
+	}
+	getRawReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	getRawReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	GetRawWithContextStub        func(context.Context, string) (io.ReadCloser, error)
+	getRawWithContextMutex       sync.RWMutex
+	getRawWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getRawWithContextReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	getRawWithContextReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	GetWithContextStub        func(context.Context, string, interface{}) error
+	getWithContextMutex       sync.RWMutex
+	getWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 interface{}
+	}
+	getWithContextReturns struct {
+		result1 error
+	}
+	getWithContextReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PostStub        func(string, map[string]string, interface{}) error
+	postMutex       sync.RWMutex
+	postArgsForCall []struct {
+		arg1 string
+		arg2 map[string]string
+		arg3 interface{}
+	}
+	postReturns struct {
+		result1 error
+	}
+	postReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PostWithContextStub        func(context.Context, string, map[string]string, interface{}) error
+	postWithContextMutex       sync.RWMutex
+	postWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]string
+		arg4 interface{}
+	}
+	postWithContextReturns struct {
+		result1 error
+	}
+	postWithContextReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PutStub        func(string, map[string]string, interface{}) error
+	putMutex       sync.RWMutex
+	putArgsForCall []struct {
+		arg1 string
+		arg2 map[string]string
+		arg3 interface{}
+	}
+	putReturns struct {
+		result1 error
+	}
+	putReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PutWithContextStub        func(context.Context, string, map[string]string, interface{}) error
+	putWithContextMutex       sync.RWMutex
+	putWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]string
+		arg4 interface{}
+	}
+	putWithContextReturns struct {
+		result1 error
+	}
+	putWithContextReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StreamStub        func(string, cf.ResourceFunc) error
+	streamMutex       sync.RWMutex
+	streamArgsForCall []struct {
+		arg1 string
+		arg2 cf.ResourceFunc
+	}
+	streamReturns struct {
+		result1 error
+	}
+	streamReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StreamWithContextStub        func(context.Context, string, cf.ResourceFunc) error
+	streamWithContextMutex       sync.RWMutex
+	streamWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 cf.ResourceFunc
+	}
+	streamWithContextReturns struct {
+		result1 error
+	}
+	streamWithContextReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCloudController) CurrentTokens() uaa.Tokens {
+	fake.currentTokensMutex.Lock()
+	ret, specificReturn := fake.currentTokensReturnsOnCall[len(fake.currentTokensArgsForCall)]
+	fake.currentTokensArgsForCall = append(fake.currentTokensArgsForCall, struct {
+	}{})
+	stub := fake.CurrentTokensStub
+	fakeReturns := fake.currentTokensReturns
+	fake.recordInvocation("CurrentTokens", []interface{}{})
+	fake.currentTokensMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) CurrentTokensCallCount() int {
+	fake.currentTokensMutex.RLock()
+	defer fake.currentTokensMutex.RUnlock()
+	return len(fake.currentTokensArgsForCall)
+}
+
+func (fake *FakeCloudController) CurrentTokensCalls(stub func() uaa.Tokens) {
+	fake.currentTokensMutex.Lock()
+	defer fake.currentTokensMutex.Unlock()
+	fake.CurrentTokensStub = stub
+}
+
+func (fake *FakeCloudController) CurrentTokensReturns(result1 uaa.Tokens) {
+	fake.currentTokensMutex.Lock()
+	defer fake.currentTokensMutex.Unlock()
+	fake.CurrentTokensStub = nil
+	fake.currentTokensReturns = struct {
+		result1 uaa.Tokens
+	}{result1}
+}
+
+func (fake *FakeCloudController) CurrentTokensReturnsOnCall(i int, result1 uaa.Tokens) {
+	fake.currentTokensMutex.Lock()
+	defer fake.currentTokensMutex.Unlock()
+	fake.CurrentTokensStub = nil
+	if fake.currentTokensReturnsOnCall == nil {
+		fake.currentTokensReturnsOnCall = make(map[int]struct {
+			result1 uaa.Tokens
+		})
+	}
+	fake.currentTokensReturnsOnCall[i] = struct {
+		result1 uaa.Tokens
+	}{result1}
+}
+
+func (fake *FakeCloudController) CurrentUser(arg1 uaa.UserInfoFetcher) (*cf.CurrentUser, error) {
+	fake.currentUserMutex.Lock()
+	ret, specificReturn := fake.currentUserReturnsOnCall[len(fake.currentUserArgsForCall)]
+	fake.currentUserArgsForCall = append(fake.currentUserArgsForCall, struct {
+		arg1 uaa.UserInfoFetcher
+	}{arg1})
+	stub := fake.CurrentUserStub
+	fakeReturns := fake.currentUserReturns
+	fake.recordInvocation("CurrentUser", []interface{}{arg1})
+	fake.currentUserMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCloudController) CurrentUserCallCount() int {
+	fake.currentUserMutex.RLock()
+	defer fake.currentUserMutex.RUnlock()
+	return len(fake.currentUserArgsForCall)
+}
+
+func (fake *FakeCloudController) CurrentUserCalls(stub func(uaa.UserInfoFetcher) (*cf.CurrentUser, error)) {
+	fake.currentUserMutex.Lock()
+	defer fake.currentUserMutex.Unlock()
+	fake.CurrentUserStub = stub
+}
+
+func (fake *FakeCloudController) CurrentUserArgsForCall(i int) uaa.UserInfoFetcher {
+	fake.currentUserMutex.RLock()
+	defer fake.currentUserMutex.RUnlock()
+	argsForCall := fake.currentUserArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCloudController) CurrentUserReturns(result1 *cf.CurrentUser, result2 error) {
+	fake.currentUserMutex.Lock()
+	defer fake.currentUserMutex.Unlock()
+	fake.CurrentUserStub = nil
+	fake.currentUserReturns = struct {
+		result1 *cf.CurrentUser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudController) CurrentUserReturnsOnCall(i int, result1 *cf.CurrentUser, result2 error) {
+	fake.currentUserMutex.Lock()
+	defer fake.currentUserMutex.Unlock()
+	fake.CurrentUserStub = nil
+	if fake.currentUserReturnsOnCall == nil {
+		fake.currentUserReturnsOnCall = make(map[int]struct {
+			result1 *cf.CurrentUser
+			result2 error
+		})
+	}
+	fake.currentUserReturnsOnCall[i] = struct {
+		result1 *cf.CurrentUser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudController) CurrentUserWithContext(arg1 context.Context, arg2 uaa.UserInfoFetcher) (*cf.CurrentUser, error) {
+	fake.currentUserWithContextMutex.Lock()
+	ret, specificReturn := fake.currentUserWithContextReturnsOnCall[len(fake.currentUserWithContextArgsForCall)]
+	fake.currentUserWithContextArgsForCall = append(fake.currentUserWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 uaa.UserInfoFetcher
+	}{arg1, arg2})
+	stub := fake.CurrentUserWithContextStub
+	fakeReturns := fake.currentUserWithContextReturns
+	fake.recordInvocation("CurrentUserWithContext", []interface{}{arg1, arg2})
+	fake.currentUserWithContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCloudController) CurrentUserWithContextCallCount() int {
+	fake.currentUserWithContextMutex.RLock()
+	defer fake.currentUserWithContextMutex.RUnlock()
+	return len(fake.currentUserWithContextArgsForCall)
+}
+
+func (fake *FakeCloudController) CurrentUserWithContextCalls(stub func(context.Context, uaa.UserInfoFetcher) (*cf.CurrentUser, error)) {
+	fake.currentUserWithContextMutex.Lock()
+	defer fake.currentUserWithContextMutex.Unlock()
+	fake.CurrentUserWithContextStub = stub
+}
+
+func (fake *FakeCloudController) CurrentUserWithContextArgsForCall(i int) (context.Context, uaa.UserInfoFetcher) {
+	fake.currentUserWithContextMutex.RLock()
+	defer fake.currentUserWithContextMutex.RUnlock()
+	argsForCall := fake.currentUserWithContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCloudController) CurrentUserWithContextReturns(result1 *cf.CurrentUser, result2 error) {
+	fake.currentUserWithContextMutex.Lock()
+	defer fake.currentUserWithContextMutex.Unlock()
+	fake.CurrentUserWithContextStub = nil
+	fake.currentUserWithContextReturns = struct {
+		result1 *cf.CurrentUser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudController) CurrentUserWithContextReturnsOnCall(i int, result1 *cf.CurrentUser, result2 error) {
+	fake.currentUserWithContextMutex.Lock()
+	defer fake.currentUserWithContextMutex.Unlock()
+	fake.CurrentUserWithContextStub = nil
+	if fake.currentUserWithContextReturnsOnCall == nil {
+		fake.currentUserWithContextReturnsOnCall = make(map[int]struct {
+			result1 *cf.CurrentUser
+			result2 error
+		})
+	}
+	fake.currentUserWithContextReturnsOnCall[i] = struct {
+		result1 *cf.CurrentUser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudController) Delete(arg1 string, arg2 map[string]string) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 string
+		arg2 map[string]string
+	}{arg1, arg2})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeCloudController) DeleteCalls(stub func(string, map[string]string) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeCloudController) DeleteArgsForCall(i int) (string, map[string]string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCloudController) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) DeleteWithContext(arg1 context.Context, arg2 string, arg3 map[string]string) error {
+	fake.deleteWithContextMutex.Lock()
+	ret, specificReturn := fake.deleteWithContextReturnsOnCall[len(fake.deleteWithContextArgsForCall)]
+	fake.deleteWithContextArgsForCall = append(fake.deleteWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]string
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteWithContextStub
+	fakeReturns := fake.deleteWithContextReturns
+	fake.recordInvocation("DeleteWithContext", []interface{}{arg1, arg2, arg3})
+	fake.deleteWithContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) DeleteWithContextCallCount() int {
+	fake.deleteWithContextMutex.RLock()
+	defer fake.deleteWithContextMutex.RUnlock()
+	return len(fake.deleteWithContextArgsForCall)
+}
+
+func (fake *FakeCloudController) DeleteWithContextCalls(stub func(context.Context, string, map[string]string) error) {
+	fake.deleteWithContextMutex.Lock()
+	defer fake.deleteWithContextMutex.Unlock()
+	fake.DeleteWithContextStub = stub
+}
+
+func (fake *FakeCloudController) DeleteWithContextArgsForCall(i int) (context.Context, string, map[string]string) {
+	fake.deleteWithContextMutex.RLock()
+	defer fake.deleteWithContextMutex.RUnlock()
+	argsForCall := fake.deleteWithContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCloudController) DeleteWithContextReturns(result1 error) {
+	fake.deleteWithContextMutex.Lock()
+	defer fake.deleteWithContextMutex.Unlock()
+	fake.DeleteWithContextStub = nil
+	fake.deleteWithContextReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) DeleteWithContextReturnsOnCall(i int, result1 error) {
+	fake.deleteWithContextMutex.Lock()
+	defer fake.deleteWithContextMutex.Unlock()
+	fake.DeleteWithContextStub = nil
+	if fake.deleteWithContextReturnsOnCall == nil {
+		fake.deleteWithContextReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteWithContextReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) Get(arg1 string, arg2 interface{}) error {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 string
+		arg2 interface{}
+	}{arg1, arg2})
+	stub := fake.GetStub
+	fakeReturns := fake.getReturns
+	fake.recordInvocation("Get", []interface{}{arg1, arg2})
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeCloudController) GetCalls(stub func(string, interface{}) error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *FakeCloudController) GetArgsForCall(i int) (string, interface{}) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	argsForCall := fake.getArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCloudController) GetReturns(result1 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) GetReturnsOnCall(i int, result1 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) GetRaw(arg1 string) (io.ReadCloser, error) {
+	fake.getRawMutex.Lock()
+	ret, specificReturn := fake.getRawReturnsOnCall[len(fake.getRawArgsForCall)]
+	fake.getRawArgsForCall = append(fake.getRawArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetRawStub
+	fakeReturns := fake.getRawReturns
+	fake.recordInvocation("GetRaw", []interface{}{arg1})
+	fake.getRawMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCloudController) GetRawCallCount() int {
+	fake.getRawMutex.RLock()
+	defer fake.getRawMutex.RUnlock()
+	return len(fake.getRawArgsForCall)
+}
+
+func (fake *FakeCloudController) GetRawCalls(stub func(string) (io.ReadCloser, error)) {
+	fake.getRawMutex.Lock()
+	defer fake.getRawMutex.Unlock()
+	fake.GetRawStub = stub
+}
+
+func (fake *FakeCloudController) GetRawArgsForCall(i int) string {
+	fake.getRawMutex.RLock()
+	defer fake.getRawMutex.RUnlock()
+	argsForCall := fake.getRawArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCloudController) GetRawReturns(result1 io.ReadCloser, result2 error) {
+	fake.getRawMutex.Lock()
+	defer fake.getRawMutex.Unlock()
+	fake.GetRawStub = nil
+	fake.getRawReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudController) GetRawReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.getRawMutex.Lock()
+	defer fake.getRawMutex.Unlock()
+	fake.GetRawStub = nil
+	if fake.getRawReturnsOnCall == nil {
+		fake.getRawReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.getRawReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudController) GetRawWithContext(arg1 context.Context, arg2 string) (io.ReadCloser, error) {
+	fake.getRawWithContextMutex.Lock()
+	ret, specificReturn := fake.getRawWithContextReturnsOnCall[len(fake.getRawWithContextArgsForCall)]
+	fake.getRawWithContextArgsForCall = append(fake.getRawWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetRawWithContextStub
+	fakeReturns := fake.getRawWithContextReturns
+	fake.recordInvocation("GetRawWithContext", []interface{}{arg1, arg2})
+	fake.getRawWithContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCloudController) GetRawWithContextCallCount() int {
+	fake.getRawWithContextMutex.RLock()
+	defer fake.getRawWithContextMutex.RUnlock()
+	return len(fake.getRawWithContextArgsForCall)
+}
+
+func (fake *FakeCloudController) GetRawWithContextCalls(stub func(context.Context, string) (io.ReadCloser, error)) {
+	fake.getRawWithContextMutex.Lock()
+	defer fake.getRawWithContextMutex.Unlock()
+	fake.GetRawWithContextStub = stub
+}
+
+func (fake *FakeCloudController) GetRawWithContextArgsForCall(i int) (context.Context, string) {
+	fake.getRawWithContextMutex.RLock()
+	defer fake.getRawWithContextMutex.RUnlock()
+	argsForCall := fake.getRawWithContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCloudController) GetRawWithContextReturns(result1 io.ReadCloser, result2 error) {
+	fake.getRawWithContextMutex.Lock()
+	defer fake.getRawWithContextMutex.Unlock()
+	fake.GetRawWithContextStub = nil
+	fake.getRawWithContextReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudController) GetRawWithContextReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.getRawWithContextMutex.Lock()
+	defer fake.getRawWithContextMutex.Unlock()
+	fake.GetRawWithContextStub = nil
+	if fake.getRawWithContextReturnsOnCall == nil {
+		fake.getRawWithContextReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.getRawWithContextReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudController) GetWithContext(arg1 context.Context, arg2 string, arg3 interface{}) error {
+	fake.getWithContextMutex.Lock()
+	ret, specificReturn := fake.getWithContextReturnsOnCall[len(fake.getWithContextArgsForCall)]
+	fake.getWithContextArgsForCall = append(fake.getWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 interface{}
+	}{arg1, arg2, arg3})
+	stub := fake.GetWithContextStub
+	fakeReturns := fake.getWithContextReturns
+	fake.recordInvocation("GetWithContext", []interface{}{arg1, arg2, arg3})
+	fake.getWithContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) GetWithContextCallCount() int {
+	fake.getWithContextMutex.RLock()
+	defer fake.getWithContextMutex.RUnlock()
+	return len(fake.getWithContextArgsForCall)
+}
+
+func (fake *FakeCloudController) GetWithContextCalls(stub func(context.Context, string, interface{}) error) {
+	fake.getWithContextMutex.Lock()
+	defer fake.getWithContextMutex.Unlock()
+	fake.GetWithContextStub = stub
+}
+
+func (fake *FakeCloudController) GetWithContextArgsForCall(i int) (context.Context, string, interface{}) {
+	fake.getWithContextMutex.RLock()
+	defer fake.getWithContextMutex.RUnlock()
+	argsForCall := fake.getWithContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCloudController) GetWithContextReturns(result1 error) {
+	fake.getWithContextMutex.Lock()
+	defer fake.getWithContextMutex.Unlock()
+	fake.GetWithContextStub = nil
+	fake.getWithContextReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) GetWithContextReturnsOnCall(i int, result1 error) {
+	fake.getWithContextMutex.Lock()
+	defer fake.getWithContextMutex.Unlock()
+	fake.GetWithContextStub = nil
+	if fake.getWithContextReturnsOnCall == nil {
+		fake.getWithContextReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.getWithContextReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) Post(arg1 string, arg2 map[string]string, arg3 interface{}) error {
+	fake.postMutex.Lock()
+	ret, specificReturn := fake.postReturnsOnCall[len(fake.postArgsForCall)]
+	fake.postArgsForCall = append(fake.postArgsForCall, struct {
+		arg1 string
+		arg2 map[string]string
+		arg3 interface{}
+	}{arg1, arg2, arg3})
+	stub := fake.PostStub
+	fakeReturns := fake.postReturns
+	fake.recordInvocation("Post", []interface{}{arg1, arg2, arg3})
+	fake.postMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) PostCallCount() int {
+	fake.postMutex.RLock()
+	defer fake.postMutex.RUnlock()
+	return len(fake.postArgsForCall)
+}
+
+func (fake *FakeCloudController) PostCalls(stub func(string, map[string]string, interface{}) error) {
+	fake.postMutex.Lock()
+	defer fake.postMutex.Unlock()
+	fake.PostStub = stub
+}
+
+func (fake *FakeCloudController) PostArgsForCall(i int) (string, map[string]string, interface{}) {
+	fake.postMutex.RLock()
+	defer fake.postMutex.RUnlock()
+	argsForCall := fake.postArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCloudController) PostReturns(result1 error) {
+	fake.postMutex.Lock()
+	defer fake.postMutex.Unlock()
+	fake.PostStub = nil
+	fake.postReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) PostReturnsOnCall(i int, result1 error) {
+	fake.postMutex.Lock()
+	defer fake.postMutex.Unlock()
+	fake.PostStub = nil
+	if fake.postReturnsOnCall == nil {
+		fake.postReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.postReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) PostWithContext(arg1 context.Context, arg2 string, arg3 map[string]string, arg4 interface{}) error {
+	fake.postWithContextMutex.Lock()
+	ret, specificReturn := fake.postWithContextReturnsOnCall[len(fake.postWithContextArgsForCall)]
+	fake.postWithContextArgsForCall = append(fake.postWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]string
+		arg4 interface{}
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.PostWithContextStub
+	fakeReturns := fake.postWithContextReturns
+	fake.recordInvocation("PostWithContext", []interface{}{arg1, arg2, arg3, arg4})
+	fake.postWithContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) PostWithContextCallCount() int {
+	fake.postWithContextMutex.RLock()
+	defer fake.postWithContextMutex.RUnlock()
+	return len(fake.postWithContextArgsForCall)
+}
+
+func (fake *FakeCloudController) PostWithContextCalls(stub func(context.Context, string, map[string]string, interface{}) error) {
+	fake.postWithContextMutex.Lock()
+	defer fake.postWithContextMutex.Unlock()
+	fake.PostWithContextStub = stub
+}
+
+func (fake *FakeCloudController) PostWithContextArgsForCall(i int) (context.Context, string, map[string]string, interface{}) {
+	fake.postWithContextMutex.RLock()
+	defer fake.postWithContextMutex.RUnlock()
+	argsForCall := fake.postWithContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeCloudController) PostWithContextReturns(result1 error) {
+	fake.postWithContextMutex.Lock()
+	defer fake.postWithContextMutex.Unlock()
+	fake.PostWithContextStub = nil
+	fake.postWithContextReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) PostWithContextReturnsOnCall(i int, result1 error) {
+	fake.postWithContextMutex.Lock()
+	defer fake.postWithContextMutex.Unlock()
+	fake.PostWithContextStub = nil
+	if fake.postWithContextReturnsOnCall == nil {
+		fake.postWithContextReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.postWithContextReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) Put(arg1 string, arg2 map[string]string, arg3 interface{}) error {
+	fake.putMutex.Lock()
+	ret, specificReturn := fake.putReturnsOnCall[len(fake.putArgsForCall)]
+	fake.putArgsForCall = append(fake.putArgsForCall, struct {
+		arg1 string
+		arg2 map[string]string
+		arg3 interface{}
+	}{arg1, arg2, arg3})
+	stub := fake.PutStub
+	fakeReturns := fake.putReturns
+	fake.recordInvocation("Put", []interface{}{arg1, arg2, arg3})
+	fake.putMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) PutCallCount() int {
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	return len(fake.putArgsForCall)
+}
+
+func (fake *FakeCloudController) PutCalls(stub func(string, map[string]string, interface{}) error) {
+	fake.putMutex.Lock()
+	defer fake.putMutex.Unlock()
+	fake.PutStub = stub
+}
+
+func (fake *FakeCloudController) PutArgsForCall(i int) (string, map[string]string, interface{}) {
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	argsForCall := fake.putArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCloudController) PutReturns(result1 error) {
+	fake.putMutex.Lock()
+	defer fake.putMutex.Unlock()
+	fake.PutStub = nil
+	fake.putReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) PutReturnsOnCall(i int, result1 error) {
+	fake.putMutex.Lock()
+	defer fake.putMutex.Unlock()
+	fake.PutStub = nil
+	if fake.putReturnsOnCall == nil {
+		fake.putReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) PutWithContext(arg1 context.Context, arg2 string, arg3 map[string]string, arg4 interface{}) error {
+	fake.putWithContextMutex.Lock()
+	ret, specificReturn := fake.putWithContextReturnsOnCall[len(fake.putWithContextArgsForCall)]
+	fake.putWithContextArgsForCall = append(fake.putWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]string
+		arg4 interface{}
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.PutWithContextStub
+	fakeReturns := fake.putWithContextReturns
+	fake.recordInvocation("PutWithContext", []interface{}{arg1, arg2, arg3, arg4})
+	fake.putWithContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) PutWithContextCallCount() int {
+	fake.putWithContextMutex.RLock()
+	defer fake.putWithContextMutex.RUnlock()
+	return len(fake.putWithContextArgsForCall)
+}
+
+func (fake *FakeCloudController) PutWithContextCalls(stub func(context.Context, string, map[string]string, interface{}) error) {
+	fake.putWithContextMutex.Lock()
+	defer fake.putWithContextMutex.Unlock()
+	fake.PutWithContextStub = stub
+}
+
+func (fake *FakeCloudController) PutWithContextArgsForCall(i int) (context.Context, string, map[string]string, interface{}) {
+	fake.putWithContextMutex.RLock()
+	defer fake.putWithContextMutex.RUnlock()
+	argsForCall := fake.putWithContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeCloudController) PutWithContextReturns(result1 error) {
+	fake.putWithContextMutex.Lock()
+	defer fake.putWithContextMutex.Unlock()
+	fake.PutWithContextStub = nil
+	fake.putWithContextReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) PutWithContextReturnsOnCall(i int, result1 error) {
+	fake.putWithContextMutex.Lock()
+	defer fake.putWithContextMutex.Unlock()
+	fake.PutWithContextStub = nil
+	if fake.putWithContextReturnsOnCall == nil {
+		fake.putWithContextReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putWithContextReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) Stream(arg1 string, arg2 cf.ResourceFunc) error {
+	fake.streamMutex.Lock()
+	ret, specificReturn := fake.streamReturnsOnCall[len(fake.streamArgsForCall)]
+	fake.streamArgsForCall = append(fake.streamArgsForCall, struct {
+		arg1 string
+		arg2 cf.ResourceFunc
+	}{arg1, arg2})
+	stub := fake.StreamStub
+	fakeReturns := fake.streamReturns
+	fake.recordInvocation("Stream", []interface{}{arg1, arg2})
+	fake.streamMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) StreamCallCount() int {
+	fake.streamMutex.RLock()
+	defer fake.streamMutex.RUnlock()
+	return len(fake.streamArgsForCall)
+}
+
+func (fake *FakeCloudController) StreamCalls(stub func(string, cf.ResourceFunc) error) {
+	fake.streamMutex.Lock()
+	defer fake.streamMutex.Unlock()
+	fake.StreamStub = stub
+}
+
+func (fake *FakeCloudController) StreamArgsForCall(i int) (string, cf.ResourceFunc) {
+	fake.streamMutex.RLock()
+	defer fake.streamMutex.RUnlock()
+	argsForCall := fake.streamArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCloudController) StreamReturns(result1 error) {
+	fake.streamMutex.Lock()
+	defer fake.streamMutex.Unlock()
+	fake.StreamStub = nil
+	fake.streamReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) StreamReturnsOnCall(i int, result1 error) {
+	fake.streamMutex.Lock()
+	defer fake.streamMutex.Unlock()
+	fake.StreamStub = nil
+	if fake.streamReturnsOnCall == nil {
+		fake.streamReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) StreamWithContext(arg1 context.Context, arg2 string, arg3 cf.ResourceFunc) error {
+	fake.streamWithContextMutex.Lock()
+	ret, specificReturn := fake.streamWithContextReturnsOnCall[len(fake.streamWithContextArgsForCall)]
+	fake.streamWithContextArgsForCall = append(fake.streamWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 cf.ResourceFunc
+	}{arg1, arg2, arg3})
+	stub := fake.StreamWithContextStub
+	fakeReturns := fake.streamWithContextReturns
+	fake.recordInvocation("StreamWithContext", []interface{}{arg1, arg2, arg3})
+	fake.streamWithContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCloudController) StreamWithContextCallCount() int {
+	fake.streamWithContextMutex.RLock()
+	defer fake.streamWithContextMutex.RUnlock()
+	return len(fake.streamWithContextArgsForCall)
+}
+
+func (fake *FakeCloudController) StreamWithContextCalls(stub func(context.Context, string, cf.ResourceFunc) error) {
+	fake.streamWithContextMutex.Lock()
+	defer fake.streamWithContextMutex.Unlock()
+	fake.StreamWithContextStub = stub
+}
+
+func (fake *FakeCloudController) StreamWithContextArgsForCall(i int) (context.Context, string, cf.ResourceFunc) {
+	fake.streamWithContextMutex.RLock()
+	defer fake.streamWithContextMutex.RUnlock()
+	argsForCall := fake.streamWithContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCloudController) StreamWithContextReturns(result1 error) {
+	fake.streamWithContextMutex.Lock()
+	defer fake.streamWithContextMutex.Unlock()
+	fake.StreamWithContextStub = nil
+	fake.streamWithContextReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) StreamWithContextReturnsOnCall(i int, result1 error) {
+	fake.streamWithContextMutex.Lock()
+	defer fake.streamWithContextMutex.Unlock()
+	fake.StreamWithContextStub = nil
+	if fake.streamWithContextReturnsOnCall == nil {
+		fake.streamWithContextReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamWithContextReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCloudController) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.currentTokensMutex.RLock()
+	defer fake.currentTokensMutex.RUnlock()
+	fake.currentUserMutex.RLock()
+	defer fake.currentUserMutex.RUnlock()
+	fake.currentUserWithContextMutex.RLock()
+	defer fake.currentUserWithContextMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.deleteWithContextMutex.RLock()
+	defer fake.deleteWithContextMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.getRawMutex.RLock()
+	defer fake.getRawMutex.RUnlock()
+	fake.getRawWithContextMutex.RLock()
+	defer fake.getRawWithContextMutex.RUnlock()
+	fake.getWithContextMutex.RLock()
+	defer fake.getWithContextMutex.RUnlock()
+	fake.postMutex.RLock()
+	defer fake.postMutex.RUnlock()
+	fake.postWithContextMutex.RLock()
+	defer fake.postWithContextMutex.RUnlock()
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	fake.putWithContextMutex.RLock()
+	defer fake.putWithContextMutex.RUnlock()
+	fake.streamMutex.RLock()
+	defer fake.streamMutex.RUnlock()
+	fake.streamWithContextMutex.RLock()
+	defer fake.streamWithContextMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeCloudController) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ cf.CloudController = new(FakeCloudController)
@@ -0,0 +1,238 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tscolari/cfapi/cf"
+	"github.com/tscolari/cfapi/uaa"
+)
+
+type FakeRefreshHooks struct {
+	AfterRefreshStub        func(context.Context, uaa.Tokens) error
+	afterRefreshMutex       sync.RWMutex
+	afterRefreshArgsForCall []struct {
+		arg1 context.Context
+		arg2 uaa.Tokens
+	}
+	afterRefreshReturns struct {
+		result1 error
+	}
+	afterRefreshReturnsOnCall map[int]struct {
+		result1 error
+	}
+	BeforeRefreshStub        func(context.Context, uaa.Tokens)
+	beforeRefreshMutex       sync.RWMutex
+	beforeRefreshArgsForCall []struct {
+		arg1 context.Context
+		arg2 uaa.Tokens
+	}
+	OnReauthenticatedStub        func(context.Context, uaa.Tokens)
+	onReauthenticatedMutex       sync.RWMutex
+	onReauthenticatedArgsForCall []struct {
+		arg1 context.Context
+		arg2 uaa.Tokens
+	}
+	OnRefreshFailureStub        func(context.Context, error)
+	onRefreshFailureMutex       sync.RWMutex
+	onRefreshFailureArgsForCall []struct {
+		arg1 context.Context
+		arg2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeRefreshHooks) AfterRefresh(arg1 context.Context, arg2 uaa.Tokens) error {
+	fake.afterRefreshMutex.Lock()
+	ret, specificReturn := fake.afterRefreshReturnsOnCall[len(fake.afterRefreshArgsForCall)]
+	fake.afterRefreshArgsForCall = append(fake.afterRefreshArgsForCall, struct {
+		arg1 context.Context
+		arg2 uaa.Tokens
+	}{arg1, arg2})
+	stub := fake.AfterRefreshStub
+	fakeReturns := fake.afterRefreshReturns
+	fake.recordInvocation("AfterRefresh", []interface{}{arg1, arg2})
+	fake.afterRefreshMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRefreshHooks) AfterRefreshCallCount() int {
+	fake.afterRefreshMutex.RLock()
+	defer fake.afterRefreshMutex.RUnlock()
+	return len(fake.afterRefreshArgsForCall)
+}
+
+func (fake *FakeRefreshHooks) AfterRefreshCalls(stub func(context.Context, uaa.Tokens) error) {
+	fake.afterRefreshMutex.Lock()
+	defer fake.afterRefreshMutex.Unlock()
+	fake.AfterRefreshStub = stub
+}
+
+func (fake *FakeRefreshHooks) AfterRefreshArgsForCall(i int) (context.Context, uaa.Tokens) {
+	fake.afterRefreshMutex.RLock()
+	defer fake.afterRefreshMutex.RUnlock()
+	argsForCall := fake.afterRefreshArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRefreshHooks) AfterRefreshReturns(result1 error) {
+	fake.afterRefreshMutex.Lock()
+	defer fake.afterRefreshMutex.Unlock()
+	fake.AfterRefreshStub = nil
+	fake.afterRefreshReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRefreshHooks) AfterRefreshReturnsOnCall(i int, result1 error) {
+	fake.afterRefreshMutex.Lock()
+	defer fake.afterRefreshMutex.Unlock()
+	fake.AfterRefreshStub = nil
+	if fake.afterRefreshReturnsOnCall == nil {
+		fake.afterRefreshReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.afterRefreshReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRefreshHooks) BeforeRefresh(arg1 context.Context, arg2 uaa.Tokens) {
+	fake.beforeRefreshMutex.Lock()
+	fake.beforeRefreshArgsForCall = append(fake.beforeRefreshArgsForCall, struct {
+		arg1 context.Context
+		arg2 uaa.Tokens
+	}{arg1, arg2})
+	stub := fake.BeforeRefreshStub
+	fake.recordInvocation("BeforeRefresh", []interface{}{arg1, arg2})
+	fake.beforeRefreshMutex.Unlock()
+	if stub != nil {
+		fake.BeforeRefreshStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeRefreshHooks) BeforeRefreshCallCount() int {
+	fake.beforeRefreshMutex.RLock()
+	defer fake.beforeRefreshMutex.RUnlock()
+	return len(fake.beforeRefreshArgsForCall)
+}
+
+func (fake *FakeRefreshHooks) BeforeRefreshCalls(stub func(context.Context, uaa.Tokens)) {
+	fake.beforeRefreshMutex.Lock()
+	defer fake.beforeRefreshMutex.Unlock()
+	fake.BeforeRefreshStub = stub
+}
+
+func (fake *FakeRefreshHooks) BeforeRefreshArgsForCall(i int) (context.Context, uaa.Tokens) {
+	fake.beforeRefreshMutex.RLock()
+	defer fake.beforeRefreshMutex.RUnlock()
+	argsForCall := fake.beforeRefreshArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRefreshHooks) OnReauthenticated(arg1 context.Context, arg2 uaa.Tokens) {
+	fake.onReauthenticatedMutex.Lock()
+	fake.onReauthenticatedArgsForCall = append(fake.onReauthenticatedArgsForCall, struct {
+		arg1 context.Context
+		arg2 uaa.Tokens
+	}{arg1, arg2})
+	stub := fake.OnReauthenticatedStub
+	fake.recordInvocation("OnReauthenticated", []interface{}{arg1, arg2})
+	fake.onReauthenticatedMutex.Unlock()
+	if stub != nil {
+		fake.OnReauthenticatedStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeRefreshHooks) OnReauthenticatedCallCount() int {
+	fake.onReauthenticatedMutex.RLock()
+	defer fake.onReauthenticatedMutex.RUnlock()
+	return len(fake.onReauthenticatedArgsForCall)
+}
+
+func (fake *FakeRefreshHooks) OnReauthenticatedCalls(stub func(context.Context, uaa.Tokens)) {
+	fake.onReauthenticatedMutex.Lock()
+	defer fake.onReauthenticatedMutex.Unlock()
+	fake.OnReauthenticatedStub = stub
+}
+
+func (fake *FakeRefreshHooks) OnReauthenticatedArgsForCall(i int) (context.Context, uaa.Tokens) {
+	fake.onReauthenticatedMutex.RLock()
+	defer fake.onReauthenticatedMutex.RUnlock()
+	argsForCall := fake.onReauthenticatedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRefreshHooks) OnRefreshFailure(arg1 context.Context, arg2 error) {
+	fake.onRefreshFailureMutex.Lock()
+	fake.onRefreshFailureArgsForCall = append(fake.onRefreshFailureArgsForCall, struct {
+		arg1 context.Context
+		arg2 error
+	}{arg1, arg2})
+	stub := fake.OnRefreshFailureStub
+	fake.recordInvocation("OnRefreshFailure", []interface{}{arg1, arg2})
+	fake.onRefreshFailureMutex.Unlock()
+	if stub != nil {
+		fake.OnRefreshFailureStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeRefreshHooks) OnRefreshFailureCallCount() int {
+	fake.onRefreshFailureMutex.RLock()
+	defer fake.onRefreshFailureMutex.RUnlock()
+	return len(fake.onRefreshFailureArgsForCall)
+}
+
+func (fake *FakeRefreshHooks) OnRefreshFailureCalls(stub func(context.Context, error)) {
+	fake.onRefreshFailureMutex.Lock()
+	defer fake.onRefreshFailureMutex.Unlock()
+	fake.OnRefreshFailureStub = stub
+}
+
+func (fake *FakeRefreshHooks) OnRefreshFailureArgsForCall(i int) (context.Context, error) {
+	fake.onRefreshFailureMutex.RLock()
+	defer fake.onRefreshFailureMutex.RUnlock()
+	argsForCall := fake.onRefreshFailureArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRefreshHooks) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.afterRefreshMutex.RLock()
+	defer fake.afterRefreshMutex.RUnlock()
+	fake.beforeRefreshMutex.RLock()
+	defer fake.beforeRefreshMutex.RUnlock()
+	fake.onReauthenticatedMutex.RLock()
+	defer fake.onReauthenticatedMutex.RUnlock()
+	fake.onRefreshFailureMutex.RLock()
+	defer fake.onRefreshFailureMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeRefreshHooks) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ cf.RefreshHooks = new(FakeRefreshHooks)
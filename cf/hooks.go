@@ -0,0 +1,27 @@
+package cf
+
+import (
+	"context"
+
+	"github.com/tscolari/cfapi/uaa"
+)
+
+//go:generate counterfeiter -o fakes/fake_refresh_hooks.go . RefreshHooks
+
+type RefreshHooks interface {
+	BeforeRefresh(ctx context.Context, tokens uaa.Tokens)
+	AfterRefresh(ctx context.Context, tokens uaa.Tokens) error
+	OnRefreshFailure(ctx context.Context, err error)
+	OnReauthenticated(ctx context.Context, tokens uaa.Tokens)
+}
+
+// NoopRefreshHooks can be embedded to implement only some of the hooks.
+type NoopRefreshHooks struct{}
+
+func (NoopRefreshHooks) BeforeRefresh(context.Context, uaa.Tokens) {}
+
+func (NoopRefreshHooks) AfterRefresh(context.Context, uaa.Tokens) error { return nil }
+
+func (NoopRefreshHooks) OnRefreshFailure(context.Context, error) {}
+
+func (NoopRefreshHooks) OnReauthenticated(context.Context, uaa.Tokens) {}
@@ -1,19 +1,34 @@
 package cf
 
-import "github.com/tscolari/cfapi/uaa"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/tscolari/cfapi/telemetry"
+	"github.com/tscolari/cfapi/uaa"
+)
 
 type RefresherClient struct {
 	Client
-	cfEndpoint     string
-	tokens         uaa.Tokens
-	uaaRefresher   uaa.Refresher
-	OnTokenRefresh func(newTokens uaa.Tokens)
+	cfEndpoint          string
+	tokens              uaa.Tokens
+	uaaRefresher        uaa.Refresher
+	tokenSource         oauth2.TokenSource
+	CredentialsProvider uaa.CredentialsProvider
+	Hooks               RefreshHooks
 }
 
 func NewRefresherClient(cfEndpoint string, tokens uaa.Tokens, uaaRefresher uaa.Refresher) *RefresherClient {
-	cfClient := *NewClient(cfEndpoint, tokens.AccessToken)
 	return &RefresherClient{
-		Client:       cfClient,
+		Client: Client{
+			accessToken: tokens.AccessToken,
+			endpoint:    cfEndpoint,
+			client:      &http.Client{},
+		},
 		cfEndpoint:   cfEndpoint,
 		tokens:       tokens,
 		uaaRefresher: uaaRefresher,
@@ -21,45 +36,202 @@ func NewRefresherClient(cfEndpoint string, tokens uaa.Tokens, uaaRefresher uaa.R
 }
 
 func (c *RefresherClient) Get(path string, response interface{}) error {
-	return c.fetch("GET", path, nil, response)
+	return c.GetWithContext(context.Background(), path, response)
 }
 
 func (c *RefresherClient) Put(path string, options map[string]string, response interface{}) error {
-	return c.fetch("PUT", path, options, response)
+	return c.PutWithContext(context.Background(), path, options, response)
 }
 
 func (c *RefresherClient) Post(path string, options map[string]string, response interface{}) error {
-	return c.fetch("POST", path, options, response)
+	return c.PostWithContext(context.Background(), path, options, response)
 }
 
 func (c *RefresherClient) Delete(path string, options map[string]string) error {
-	return c.fetch("DELETE", path, options, nil)
+	return c.DeleteWithContext(context.Background(), path, options)
+}
+
+func (c *RefresherClient) GetWithContext(ctx context.Context, path string, response interface{}) error {
+	return c.fetch(ctx, "GET", path, nil, response)
+}
+
+func (c *RefresherClient) PutWithContext(ctx context.Context, path string, options map[string]string, response interface{}) error {
+	return c.fetch(ctx, "PUT", path, options, response)
+}
+
+func (c *RefresherClient) PostWithContext(ctx context.Context, path string, options map[string]string, response interface{}) error {
+	return c.fetch(ctx, "POST", path, options, response)
+}
+
+func (c *RefresherClient) DeleteWithContext(ctx context.Context, path string, options map[string]string) error {
+	return c.fetch(ctx, "DELETE", path, options, nil)
+}
+
+func (c *RefresherClient) Logout() error {
+	return c.LogoutWithContext(context.Background())
+}
+
+func (c *RefresherClient) LogoutWithContext(ctx context.Context) error {
+	var err error
+	revoker, ok := c.uaaRefresher.(uaa.Revoker)
+	if !ok {
+		err = errors.New("Refresher does not support token revocation")
+	} else if c.currentTokens().RefreshToken != "" {
+		err = c.revokeRefreshToken(ctx, revoker)
+	}
+
+	c.setTokens(uaa.Tokens{})
+
+	return err
 }
 
-func (c *RefresherClient) fetch(method, path string, options map[string]string, response interface{}) error {
-	err := c.Client.fetch(method, path, options, response)
-	if err != nil && err.Error() == "Unauthorized" {
-		err = c.refreshTokens()
+func (c *RefresherClient) revokeRefreshToken(ctx context.Context, revoker uaa.Revoker) error {
+	err := c.revoke(ctx, revoker)
+	if uaaErr, ok := err.(*uaa.Error); ok && uaaErr.StatusCode == http.StatusUnauthorized {
+		err = c.refreshTokens(ctx)
 		if err != nil {
 			return err
 		}
-		return c.Client.fetch(method, path, options, response)
+		return c.revoke(ctx, revoker)
+	}
+	return err
+}
+
+func (c *RefresherClient) revoke(ctx context.Context, revoker uaa.Revoker) error {
+	tokens := c.currentTokens()
+	tokenID := uaa.TokenID(tokens.RefreshToken)
+	if contextRevoker, ok := revoker.(uaa.ContextRevoker); ok {
+		return contextRevoker.RevokeTokenWithContext(ctx, tokens.AccessToken, tokenID)
+	}
+
+	return revoker.RevokeToken(tokens.AccessToken, tokenID)
+}
+
+func (c *RefresherClient) currentTokens() uaa.Tokens {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+	return c.tokens
+}
+
+func (c *RefresherClient) setTokens(tokens uaa.Tokens) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	c.tokens = tokens
+	c.accessToken = tokens.AccessToken
+}
+
+func (c *RefresherClient) fetch(ctx context.Context, method, path string, options map[string]string, response interface{}) error {
+	return c.do(ctx, method, path, options, func(resp *http.Response) error {
+		return c.parseResponse(resp, response)
+	})
+}
+
+func (c *RefresherClient) do(ctx context.Context, method, path string, options map[string]string, handle responseHandler) error {
+	err := c.syncTokenSource()
+	if err != nil {
+		return err
+	}
+
+	var unauthorized bool
+	accessToken := c.currentAccessToken()
+	err = c.Client.do(ctx, method, path, options, func(resp *http.Response) error {
+		unauthorized = resp.StatusCode == http.StatusUnauthorized
+		return handle(resp)
+	})
+	if err != nil && unauthorized {
+		refreshErr := c.refreshTokens(ctx)
+		if refreshErr != nil {
+			return refreshErr
+		}
+		// Retrying with the token that was just rejected would only fail
+		// again, e.g. with a token source that caches until expiry.
+		if c.currentAccessToken() == accessToken {
+			return err
+		}
+		return c.retry(ctx, method, path, options, handle)
 	}
 	return err
 }
 
-func (c *RefresherClient) refreshTokens() error {
-	tokens, err := c.uaaRefresher.RefreshToken(c.tokens.RefreshToken)
+func (c *RefresherClient) retry(ctx context.Context, method, path string, options map[string]string, handle responseHandler) (err error) {
+	ctx, span := telemetry.StartSpan(c.Tracer, ctx, "cf retry")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	return c.Client.do(ctx, method, path, options, handle)
+}
+
+func (c *RefresherClient) refreshTokens(ctx context.Context) (err error) {
+	ctx, span := telemetry.StartSpan(c.Tracer, ctx, "cf refresh tokens")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	hooks := c.hooks()
+	hooks.BeforeRefresh(ctx, c.currentTokens())
+
+	start := time.Now()
+	tokens, reauthenticated, err := c.refreshOrReauthenticate(ctx)
+	if c.Metrics != nil {
+		c.Metrics.ObserveTokenRefresh(time.Since(start), err)
+	}
 	if err != nil {
+		hooks.OnRefreshFailure(ctx, err)
 		return err
 	}
 
-	c.tokens = *tokens
-	c.Client = *NewClient(c.cfEndpoint, tokens.AccessToken)
+	c.setTokens(*tokens)
+
+	if reauthenticated {
+		hooks.OnReauthenticated(ctx, *tokens)
+	}
+
+	return hooks.AfterRefresh(ctx, *tokens)
+}
+
+func (c *RefresherClient) hooks() RefreshHooks {
+	if c.Hooks == nil {
+		return NoopRefreshHooks{}
+	}
+
+	return c.Hooks
+}
+
+func (c *RefresherClient) refreshOrReauthenticate(ctx context.Context) (*uaa.Tokens, bool, error) {
+	if c.CredentialsProvider == nil {
+		tokens, err := c.refreshToken(ctx)
+		return tokens, false, err
+	}
+
+	if c.currentTokens().RefreshToken != "" {
+		tokens, err := c.refreshToken(ctx)
+		if !uaa.IsInvalidGrant(err) {
+			return tokens, false, err
+		}
+	}
+
+	tokens, err := c.reauthenticate(ctx)
+	return tokens, err == nil, err
+}
+
+func (c *RefresherClient) reauthenticate(ctx context.Context) (tokens *uaa.Tokens, err error) {
+	ctx, span := telemetry.StartSpan(c.Tracer, ctx, "cf reauthenticate")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	authenticator, ok := c.uaaRefresher.(uaa.CredentialsAuthenticator)
+	if !ok {
+		return nil, errors.New("Refresher does not support re-authentication")
+	}
+
+	credentials, err := c.CredentialsProvider.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return authenticator.AuthenticateWithCredentialsWithContext(ctx, *credentials)
+}
 
-	if c.OnTokenRefresh != nil {
-		c.OnTokenRefresh(c.tokens)
+func (c *RefresherClient) refreshToken(ctx context.Context) (*uaa.Tokens, error) {
+	if refresher, ok := c.uaaRefresher.(uaa.ContextRefresher); ok {
+		return refresher.RefreshTokenWithContext(ctx, c.currentTokens().RefreshToken)
 	}
 
-	return nil
+	return c.uaaRefresher.RefreshToken(c.currentTokens().RefreshToken)
 }
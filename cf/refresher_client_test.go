@@ -1,11 +1,17 @@
 package cf_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 
-	"github.com/cloudfoundry/cli/cf/api/resources"
 	"github.com/tscolari/cfapi/cf"
+	"github.com/tscolari/cfapi/metrics"
+	"github.com/tscolari/cfapi/telemetry"
+	telemetryfakes "github.com/tscolari/cfapi/telemetry/fakes"
+	"github.com/tscolari/cfapi/trace"
 	"github.com/tscolari/cfapi/uaa"
 	uaafakes "github.com/tscolari/cfapi/uaa/fakes"
 
@@ -19,10 +25,8 @@ var _ = Describe("RefresherClient", func() {
 	var tokens uaa.Tokens
 	var uaaRefresher *uaafakes.FakeRefresher
 	var client *cf.RefresherClient
-	var response resources.ApplicationResource
 
 	JustBeforeEach(func() {
-		response = resources.ApplicationResource{}
 		server = httptest.NewServer(handlerFunc)
 		client = cf.NewRefresherClient(server.URL, tokens, uaaRefresher)
 	})
@@ -77,16 +81,360 @@ var _ = Describe("RefresherClient", func() {
 			Expect(uaaRefresher.RefreshTokenArgsForCall(0)).To(Equal("old-refresh-token"))
 		})
 
-		Context("when `OnTokenRefresh` is given", func() {
-			It("calls with the updated tokens", func() {
-				client.OnTokenRefresh = func(newTokens uaa.Tokens) {
-					Expect(newTokens.AccessToken).To(Equal("refreshed-access-token"))
-					Expect(newTokens.RefreshToken).To(Equal("another-refresh-token"))
+		Context("when Hooks are given", func() {
+			var hooks *recordingHooks
+
+			JustBeforeEach(func() {
+				hooks = new(recordingHooks)
+				client.Hooks = hooks
+			})
+
+			It("calls them around the refresh", func() {
+				err := client.Get("/app/123", nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(hooks.events).To(Equal([]string{
+					"before old-refresh-token",
+					"after refreshed-access-token another-refresh-token",
+				}))
+			})
+
+			Context("when AfterRefresh fails", func() {
+				JustBeforeEach(func() {
+					hooks.afterErr = errors.New("failed to save tokens")
+				})
+
+				It("returns the error without retrying", func() {
+					err := client.Get("/app/123", nil)
+					Expect(err).To(MatchError("failed to save tokens"))
+					Expect(client.CurrentTokens().AccessToken).To(Equal("refreshed-access-token"))
+				})
+			})
+
+			Context("when the refresh fails", func() {
+				BeforeEach(func() {
+					uaaRefresher.RefreshTokenReturns(nil, errors.New("refresh failed"))
+				})
+
+				It("notifies the failure", func() {
+					err := client.Get("/app/123", nil)
+					Expect(err).To(MatchError("refresh failed"))
+
+					Expect(hooks.events).To(Equal([]string{
+						"before old-refresh-token",
+						"failure refresh failed",
+					}))
+				})
+			})
+		})
+
+		It("keeps the TracePrinter after refreshing", func() {
+			output := new(bytes.Buffer)
+			client.TracePrinter = trace.NewWriterPrinter(output)
+
+			err := client.Get("/app/123", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output.String()).To(ContainSubstring("401 Unauthorized"))
+			Expect(output.String()).To(ContainSubstring("200 OK"))
+		})
+
+		Context("when a Tracer is set", func() {
+			var tracer *telemetryfakes.FakeTracer
+
+			JustBeforeEach(func() {
+				tracer = new(telemetryfakes.FakeTracer)
+				tracer.StartStub = func(ctx context.Context, name string) (context.Context, telemetry.Span) {
+					return ctx, new(telemetryfakes.FakeSpan)
 				}
+				client.Tracer = tracer
+			})
+
+			It("creates spans for the refresh and the retry", func() {
+				err := client.Get("/app/123", nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				names := []string{}
+				for i := 0; i < tracer.StartCallCount(); i++ {
+					_, name := tracer.StartArgsForCall(i)
+					names = append(names, name)
+				}
+				Expect(names).To(Equal([]string{"cf GET /app/:id", "cf refresh tokens", "cf retry", "cf GET /app/:id"}))
+			})
+		})
+
+		Context("when the refresh token is rejected", func() {
+			var authenticator *uaafakes.FakeCredentialsAuthenticator
+			var credentialsCalls int
+
+			BeforeEach(func() {
+				credentialsCalls = 0
+				uaaRefresher.RefreshTokenReturns(nil, &uaa.Error{StatusCode: 401, ErrorCode: "invalid_grant", Description: "Invalid refresh token"})
+				authenticator = new(uaafakes.FakeCredentialsAuthenticator)
+				authenticator.AuthenticateWithCredentialsWithContextReturns(&uaa.Tokens{
+					AccessToken:  "refreshed-access-token",
+					RefreshToken: "fresh-refresh-token",
+				}, nil)
+			})
+
+			JustBeforeEach(func() {
+				client = cf.NewRefresherClient(server.URL, tokens, &reauthenticatingRefresher{uaaRefresher, authenticator})
+				client.CredentialsProvider = uaa.CredentialsProviderFunc(func(ctx context.Context) (*uaa.Credentials, error) {
+					credentialsCalls++
+					return &uaa.Credentials{GrantType: "password", Username: "admin", Password: "admin-password"}, nil
+				})
+			})
+
+			It("re-authenticates with the credentials and retries the request", func() {
+				err := client.Get("/app/123", nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(uaaRefresher.RefreshTokenCallCount()).To(Equal(1))
+				Expect(credentialsCalls).To(Equal(1))
+				Expect(authenticator.AuthenticateWithCredentialsWithContextCallCount()).To(Equal(1))
+				_, credentials := authenticator.AuthenticateWithCredentialsWithContextArgsForCall(0)
+				Expect(credentials).To(Equal(uaa.Credentials{GrantType: "password", Username: "admin", Password: "admin-password"}))
+
+				Expect(client.CurrentTokens().AccessToken).To(Equal("refreshed-access-token"))
+			})
+
+			Context("when there is no refresh token", func() {
+				BeforeEach(func() {
+					tokens.RefreshToken = ""
+				})
+
+				It("re-authenticates straight away", func() {
+					Expect(client.Get("/app/123", nil)).To(Succeed())
+					Expect(uaaRefresher.RefreshTokenCallCount()).To(Equal(0))
+					Expect(credentialsCalls).To(Equal(1))
+				})
+			})
+
+			It("notifies the re-authentication", func() {
+				hooks := new(recordingHooks)
+				client.Hooks = hooks
+
+				Expect(client.Get("/app/123", nil)).To(Succeed())
+				Expect(hooks.events).To(Equal([]string{
+					"before old-refresh-token",
+					"reauthenticated refreshed-access-token",
+					"after refreshed-access-token fresh-refresh-token",
+				}))
+			})
+
+			It("returns the re-authentication error", func() {
+				authenticator.AuthenticateWithCredentialsWithContextReturns(nil, errors.New("bad credentials"))
+
+				err := client.Get("/app/123", nil)
+				Expect(err).To(MatchError("bad credentials"))
+			})
+
+			It("doesn't re-authenticate for other refresh failures", func() {
+				uaaRefresher.RefreshTokenReturns(nil, errors.New("connection refused"))
+
+				err := client.Get("/app/123", nil)
+				Expect(err).To(MatchError("connection refused"))
+				Expect(credentialsCalls).To(Equal(0))
+			})
+
+			Context("when the refresher can't authenticate", func() {
+				JustBeforeEach(func() {
+					provider := client.CredentialsProvider
+					client = cf.NewRefresherClient(server.URL, tokens, uaaRefresher)
+					client.CredentialsProvider = provider
+				})
+
+				It("returns an error", func() {
+					err := client.Get("/app/123", nil)
+					Expect(err).To(MatchError("Refresher does not support re-authentication"))
+				})
+			})
+
+			Context("without a CredentialsProvider", func() {
+				JustBeforeEach(func() {
+					client.CredentialsProvider = nil
+				})
+
+				It("returns the refresh error", func() {
+					err := client.Get("/app/123", nil)
+					Expect(err).To(MatchError("UAA Error: Invalid refresh token (invalid_grant)"))
+				})
+			})
+		})
+
+		Context("when the refresher accepts a context", func() {
+			var contextRefresher *contextAwareRefresher
+
+			BeforeEach(func() {
+				contextRefresher = &contextAwareRefresher{FakeRefresher: uaaRefresher}
+			})
+
+			JustBeforeEach(func() {
+				client = cf.NewRefresherClient(server.URL, tokens, contextRefresher)
+			})
+
+			It("passes the request context to the refresher", func() {
+				ctx := context.WithValue(context.Background(), "key", "value")
+				err := client.GetWithContext(ctx, "/app/123", nil)
+				Expect(err).ToNot(HaveOccurred())
 
+				Expect(contextRefresher.ctx.Value("key")).To(Equal("value"))
+				Expect(uaaRefresher.RefreshTokenCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when Metrics is set", func() {
+			var (
+				collector *metrics.PrometheusCollector
+				output    *bytes.Buffer
+			)
+
+			JustBeforeEach(func() {
+				collector = metrics.NewPrometheusCollector("")
+				client.Metrics = collector
+				output = new(bytes.Buffer)
+			})
+
+			It("records the refresh attempt", func() {
 				err := client.Get("/app/123", nil)
 				Expect(err).ToNot(HaveOccurred())
+
+				collector.WriteTo(output)
+				Expect(output.String()).To(ContainSubstring("cfapi_token_refreshes_total 1\n"))
+				Expect(output.String()).To(ContainSubstring("cfapi_token_refresh_failures_total 0\n"))
+				Expect(output.String()).To(ContainSubstring(`cfapi_requests_total{method="GET",path="/app/:id",code="401"} 1`))
+				Expect(output.String()).To(ContainSubstring(`cfapi_requests_total{method="GET",path="/app/:id",code="200"} 1`))
+			})
+
+			Context("when the refresh fails", func() {
+				BeforeEach(func() {
+					uaaRefresher.RefreshTokenReturns(nil, errors.New("invalid_grant"))
+				})
+
+				It("records the failure", func() {
+					err := client.Get("/app/123", nil)
+					Expect(err).To(MatchError("invalid_grant"))
+
+					collector.WriteTo(output)
+					Expect(output.String()).To(ContainSubstring("cfapi_token_refreshes_total 1\n"))
+					Expect(output.String()).To(ContainSubstring("cfapi_token_refresh_failures_total 1\n"))
+				})
+			})
+		})
+	})
+
+	Describe("Logout", func() {
+		var uaaRevoker *uaafakes.FakeRevoker
+
+		BeforeEach(func() {
+			handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(200)
+			})
+			uaaRevoker = new(uaafakes.FakeRevoker)
+		})
+
+		JustBeforeEach(func() {
+			client = cf.NewRefresherClient(server.URL, tokens, &revokingRefresher{uaaRefresher, uaaRevoker})
+		})
+
+		It("revokes the refresh token and clears the tokens", func() {
+			err := client.Logout()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(uaaRevoker.RevokeTokenCallCount()).To(Equal(1))
+			accessToken, tokenID := uaaRevoker.RevokeTokenArgsForCall(0)
+			Expect(accessToken).To(Equal("12345"))
+			Expect(tokenID).To(Equal("old-refresh-token"))
+
+			Expect(client.CurrentTokens()).To(Equal(uaa.Tokens{}))
+		})
+
+		Context("when the access token has expired", func() {
+			BeforeEach(func() {
+				uaaRefresher.RefreshTokenReturns(&uaa.Tokens{
+					AccessToken:  "refreshed-access-token",
+					RefreshToken: "rotated-refresh-token",
+				}, nil)
+				uaaRevoker.RevokeTokenReturnsOnCall(0, &uaa.Error{StatusCode: http.StatusUnauthorized, ErrorCode: "invalid_token"})
+			})
+
+			It("refreshes the tokens and revokes the new refresh token", func() {
+				err := client.LogoutWithContext(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(uaaRefresher.RefreshTokenCallCount()).To(Equal(1))
+				Expect(uaaRevoker.RevokeTokenCallCount()).To(Equal(2))
+				accessToken, tokenID := uaaRevoker.RevokeTokenArgsForCall(1)
+				Expect(accessToken).To(Equal("refreshed-access-token"))
+				Expect(tokenID).To(Equal("rotated-refresh-token"))
+
+				Expect(client.CurrentTokens()).To(Equal(uaa.Tokens{}))
+			})
+		})
+
+		Context("when the revocation fails", func() {
+			BeforeEach(func() {
+				uaaRevoker.RevokeTokenReturns(errors.New("access denied"))
+			})
+
+			It("still clears the tokens", func() {
+				err := client.Logout()
+				Expect(err).To(MatchError("access denied"))
+				Expect(client.CurrentTokens()).To(Equal(uaa.Tokens{}))
+			})
+		})
+
+		Context("when the refresher can't revoke tokens", func() {
+			JustBeforeEach(func() {
+				client = cf.NewRefresherClient(server.URL, tokens, uaaRefresher)
+			})
+
+			It("returns an error and clears the tokens", func() {
+				err := client.Logout()
+				Expect(err).To(MatchError("Refresher does not support token revocation"))
+				Expect(client.CurrentTokens()).To(Equal(uaa.Tokens{}))
 			})
 		})
 	})
 })
+
+type recordingHooks struct {
+	events   []string
+	afterErr error
+}
+
+func (h *recordingHooks) BeforeRefresh(ctx context.Context, tokens uaa.Tokens) {
+	h.events = append(h.events, "before "+tokens.RefreshToken)
+}
+
+func (h *recordingHooks) AfterRefresh(ctx context.Context, tokens uaa.Tokens) error {
+	h.events = append(h.events, "after "+tokens.AccessToken+" "+tokens.RefreshToken)
+	return h.afterErr
+}
+
+func (h *recordingHooks) OnRefreshFailure(ctx context.Context, err error) {
+	h.events = append(h.events, "failure "+err.Error())
+}
+
+func (h *recordingHooks) OnReauthenticated(ctx context.Context, tokens uaa.Tokens) {
+	h.events = append(h.events, "reauthenticated "+tokens.AccessToken)
+}
+
+type reauthenticatingRefresher struct {
+	*uaafakes.FakeRefresher
+	*uaafakes.FakeCredentialsAuthenticator
+}
+
+type revokingRefresher struct {
+	*uaafakes.FakeRefresher
+	*uaafakes.FakeRevoker
+}
+
+type contextAwareRefresher struct {
+	*uaafakes.FakeRefresher
+	ctx context.Context
+}
+
+func (r *contextAwareRefresher) RefreshTokenWithContext(ctx context.Context, refreshToken string) (*uaa.Tokens, error) {
+	r.ctx = ctx
+	return r.RefreshToken(refreshToken)
+}
@@ -0,0 +1,223 @@
+package cf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var ErrStopStream = errors.New("stop stream")
+
+type ResourceFunc func(resource json.RawMessage) error
+
+type resourceFuncError struct {
+	err error
+}
+
+func (e resourceFuncError) Error() string {
+	return e.err.Error()
+}
+
+type requestFunc func(ctx context.Context, method, path string, options map[string]string, handle responseHandler) error
+
+func (c *Client) Stream(path string, fn ResourceFunc) error {
+	return c.StreamWithContext(context.Background(), path, fn)
+}
+
+func (c *Client) StreamWithContext(ctx context.Context, path string, fn ResourceFunc) error {
+	return c.stream(ctx, c.do, path, fn)
+}
+
+func (c *Client) GetRaw(path string) (io.ReadCloser, error) {
+	return c.GetRawWithContext(context.Background(), path)
+}
+
+func (c *Client) GetRawWithContext(ctx context.Context, path string) (io.ReadCloser, error) {
+	return c.getRaw(ctx, c.do, path)
+}
+
+func (c *RefresherClient) Stream(path string, fn ResourceFunc) error {
+	return c.StreamWithContext(context.Background(), path, fn)
+}
+
+func (c *RefresherClient) StreamWithContext(ctx context.Context, path string, fn ResourceFunc) error {
+	return c.stream(ctx, c.do, path, fn)
+}
+
+func (c *RefresherClient) GetRaw(path string) (io.ReadCloser, error) {
+	return c.GetRawWithContext(context.Background(), path)
+}
+
+func (c *RefresherClient) GetRawWithContext(ctx context.Context, path string) (io.ReadCloser, error) {
+	return c.getRaw(ctx, c.do, path)
+}
+
+func (c *Client) getRaw(ctx context.Context, do requestFunc, path string) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := do(ctx, "GET", path, nil, func(resp *http.Response) error {
+		err := c.checkResponse(resp)
+		if err != nil {
+			resp.Body.Close()
+			return err
+		}
+
+		body = resp.Body
+		return nil
+	})
+
+	return body, err
+}
+
+func (c *Client) stream(ctx context.Context, do requestFunc, path string, fn ResourceFunc) error {
+	for path != "" {
+		var next string
+		err := do(ctx, "GET", path, nil, func(resp *http.Response) error {
+			defer resp.Body.Close()
+			err := c.checkResponse(resp)
+			if err != nil {
+				return err
+			}
+
+			next, err = decodeResources(resp.Body, fn)
+			return err
+		})
+
+		if errors.Is(err, ErrStopStream) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path, err = c.relativePath(next)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) relativePath(next string) (string, error) {
+	if next == "" || strings.HasPrefix(next, "/") {
+		return next, nil
+	}
+
+	nextURL, err := url.Parse(next)
+	if err != nil {
+		return "", fmt.Errorf("Invalid next page url: %s", err.Error())
+	}
+
+	// The next page is always requested from the endpoint, only its path
+	// and query are taken from the url.
+	requestURI := nextURL.RequestURI()
+
+	endpointURL, err := url.Parse(c.endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	prefix := strings.TrimSuffix(endpointURL.Path, "/")
+	if nextURL.Scheme == endpointURL.Scheme && nextURL.Host == endpointURL.Host && prefix != "" && strings.HasPrefix(requestURI, prefix+"/") {
+		return strings.TrimPrefix(requestURI, prefix), nil
+	}
+
+	return requestURI, nil
+}
+
+func decodeResources(body io.Reader, fn ResourceFunc) (string, error) {
+	var next string
+	decoder := json.NewDecoder(body)
+
+	err := expectDelim(decoder, '{')
+	if err != nil {
+		return "", err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("Failed to parse response: %s", err.Error())
+		}
+
+		switch token {
+		case "resources":
+			err = decodeResourceList(decoder, fn)
+		case "next_url":
+			var nextURL *string
+			err = decoder.Decode(&nextURL)
+			if nextURL != nil {
+				next = *nextURL
+			}
+		case "pagination":
+			var pagination struct {
+				Next *struct {
+					Href string `json:"href"`
+				} `json:"next"`
+			}
+			err = decoder.Decode(&pagination)
+			if pagination.Next != nil {
+				next = pagination.Next.Href
+			}
+		default:
+			var skip json.RawMessage
+			err = decoder.Decode(&skip)
+		}
+
+		if callbackErr, ok := err.(resourceFuncError); ok {
+			return "", callbackErr.err
+		}
+		if err != nil {
+			return "", fmt.Errorf("Failed to parse response: %s", err.Error())
+		}
+	}
+
+	return next, nil
+}
+
+func decodeResourceList(decoder *json.Decoder, fn ResourceFunc) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return errors.New("expected resources to be an array")
+	}
+
+	for decoder.More() {
+		var resource json.RawMessage
+		err = decoder.Decode(&resource)
+		if err != nil {
+			return err
+		}
+
+		err = fn(resource)
+		if err != nil {
+			return resourceFuncError{err: err}
+		}
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+func expectDelim(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("Failed to parse response: %s", err.Error())
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf("Failed to parse response: expected %s", expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package cf_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/tscolari/cfapi/cf"
+	"github.com/tscolari/cfapi/uaa"
+	uaafakes "github.com/tscolari/cfapi/uaa/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stream", func() {
+	var (
+		server      *httptest.Server
+		handlerFunc http.Handler
+		client      *cf.Client
+		names       []string
+		collect     cf.ResourceFunc
+	)
+
+	JustBeforeEach(func() {
+		server = httptest.NewServer(handlerFunc)
+		client = cf.NewClient(server.URL, "my-access-token")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	BeforeEach(func() {
+		names = []string{}
+		collect = func(resource json.RawMessage) error {
+			var app resources.ApplicationResource
+			err := json.Unmarshal(resource, &app)
+			Expect(err).ToNot(HaveOccurred())
+			names = append(names, *app.Entity.Name)
+			return nil
+		}
+	})
+
+	Context("with v2 pagination", func() {
+		BeforeEach(func() {
+			handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Header.Get("Authorization")).To(Equal("bearer my-access-token"))
+				w.Header().Set("Content-Type", "application/json")
+
+				switch r.URL.Query().Get("page") {
+				case "":
+					w.Write([]byte(`{"total_results":3,"total_pages":2,"prev_url":null,"next_url":"/v2/apps?page=2","resources":[` +
+						`{"metadata":{"guid":"1"},"entity":{"name":"app-1"}},{"metadata":{"guid":"2"},"entity":{"name":"app-2"}}]}`))
+				case "2":
+					w.Write([]byte(`{"total_results":3,"total_pages":2,"prev_url":"/v2/apps?page=1","next_url":null,"resources":[` +
+						`{"metadata":{"guid":"3"},"entity":{"name":"app-3"}}]}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+		})
+
+		It("yields every resource across all pages", func() {
+			err := client.Stream("/v2/apps", collect)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"app-1", "app-2", "app-3"}))
+		})
+
+		It("stops when the callback returns ErrStopStream", func() {
+			err := client.Stream("/v2/apps", func(resource json.RawMessage) error {
+				collect(resource)
+				return cf.ErrStopStream
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"app-1"}))
+		})
+
+		It("stops when the callback returns a wrapped ErrStopStream", func() {
+			err := client.Stream("/v2/apps", func(resource json.RawMessage) error {
+				collect(resource)
+				return fmt.Errorf("found it: %w", cf.ErrStopStream)
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"app-1"}))
+		})
+
+		It("returns errors from the callback untouched", func() {
+			err := client.Stream("/v2/apps", func(resource json.RawMessage) error {
+				return errors.New("callback failed")
+			})
+			Expect(err).To(MatchError("callback failed"))
+		})
+	})
+
+	Context("with v3 pagination", func() {
+		BeforeEach(func() {
+			handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if r.URL.Query().Get("page") == "2" {
+					w.Write([]byte(`{"pagination":{"total_results":2,"next":null},"resources":[{"entity":{"name":"app-2"}}]}`))
+					return
+				}
+
+				next := fmt.Sprintf("http://%s/v3/apps?page=2", r.Host)
+				w.Write([]byte(`{"pagination":{"total_results":2,"next":{"href":"` + next + `"}},"resources":[{"entity":{"name":"app-1"}}]}`))
+			})
+		})
+
+		It("follows the absolute next href", func() {
+			err := client.Stream("/v3/apps", collect)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"app-1", "app-2"}))
+		})
+
+		Context("when the next href points to another host", func() {
+			BeforeEach(func() {
+				handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+
+					if r.URL.Query().Get("page") == "2" {
+						w.Write([]byte(`{"pagination":{"total_results":2,"next":null},"resources":[{"entity":{"name":"app-2"}}]}`))
+						return
+					}
+
+					next := fmt.Sprintf("http://%s@evil.example.com/v3/apps?page=2", r.Host)
+					w.Write([]byte(`{"pagination":{"total_results":2,"next":{"href":"` + next + `"}},"resources":[{"entity":{"name":"app-1"}}]}`))
+				})
+			})
+
+			It("only follows its path on the endpoint", func() {
+				err := client.Stream("/v3/apps", collect)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(names).To(Equal([]string{"app-1", "app-2"}))
+			})
+		})
+	})
+
+	Context("when cloud controller returns an error", func() {
+		BeforeEach(func() {
+			handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, `{"description": "Ups"}`, http.StatusInternalServerError)
+			})
+		})
+
+		It("returns the correct error message", func() {
+			err := client.Stream("/v2/apps", collect)
+			Expect(err).To(MatchError("Ups"))
+		})
+	})
+
+	Context("when cloud controller returns an invalid json", func() {
+		BeforeEach(func() {
+			handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"resources": [{"entity": } true}`))
+			})
+		})
+
+		It("returns the correct error message", func() {
+			err := client.Stream("/v2/apps", collect)
+			Expect(err.Error()).To(ContainSubstring("Failed to parse response"))
+		})
+	})
+
+	Context("with a RefresherClient", func() {
+		var (
+			refresherClient *cf.RefresherClient
+			uaaRefresher    *uaafakes.FakeRefresher
+		)
+
+		BeforeEach(func() {
+			uaaRefresher = new(uaafakes.FakeRefresher)
+			uaaRefresher.RefreshTokenReturns(&uaa.Tokens{AccessToken: "refreshed-access-token"}, nil)
+
+			handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("page") == "2" {
+					if r.Header.Get("Authorization") != "bearer refreshed-access-token" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					w.Write([]byte(`{"next_url":null,"resources":[{"entity":{"name":"app-2"}}]}`))
+					return
+				}
+
+				w.Write([]byte(`{"next_url":"/v2/apps?page=2","resources":[{"entity":{"name":"app-1"}}]}`))
+			})
+		})
+
+		JustBeforeEach(func() {
+			refresherClient = cf.NewRefresherClient(server.URL, uaa.Tokens{AccessToken: "old-token"}, uaaRefresher)
+		})
+
+		It("refreshes the tokens and retries the failed page", func() {
+			err := refresherClient.Stream("/v2/apps", collect)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"app-1", "app-2"}))
+			Expect(uaaRefresher.RefreshTokenCallCount()).To(Equal(1))
+		})
+
+		It("doesn't refresh the tokens when the callback fails", func() {
+			err := refresherClient.Stream("/v2/apps", func(resource json.RawMessage) error {
+				return errors.New("Unauthorized")
+			})
+			Expect(err).To(MatchError("Unauthorized"))
+			Expect(uaaRefresher.RefreshTokenCallCount()).To(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("GetRaw", func() {
+	var (
+		server      *httptest.Server
+		handlerFunc http.Handler
+		client      *cf.Client
+	)
+
+	JustBeforeEach(func() {
+		server = httptest.NewServer(handlerFunc)
+		client = cf.NewClient(server.URL, "my-access-token")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	BeforeEach(func() {
+		handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal("GET"))
+			w.Write(readResponseJSON("app-response.json"))
+		})
+	})
+
+	It("returns the unparsed response body", func() {
+		body, err := client.GetRaw("/v2/apps/123")
+		Expect(err).ToNot(HaveOccurred())
+		defer body.Close()
+
+		content, err := ioutil.ReadAll(body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(readResponseJSON("app-response.json")))
+	})
+
+	Context("when there's an authorization error", func() {
+		BeforeEach(func() {
+			handlerFunc = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, ``, http.StatusUnauthorized)
+			})
+		})
+
+		It("returns the correct error message", func() {
+			body, err := client.GetRaw("/v2/apps/123")
+			Expect(err).To(MatchError("Unauthorized"))
+			Expect(body).To(BeNil())
+		})
+	})
+})
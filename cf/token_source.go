@@ -0,0 +1,43 @@
+package cf
+
+import (
+	"golang.org/x/oauth2"
+
+	"github.com/tscolari/cfapi/uaa"
+)
+
+// NewTokenSourceClient takes its tokens from source. A request rejected with
+// 401 is only retried when source hands out a different token afterwards.
+func NewTokenSourceClient(cfEndpoint string, source oauth2.TokenSource) *RefresherClient {
+	client := NewRefresherClient(cfEndpoint, uaa.Tokens{}, tokenSourceRefresher{source: source})
+	client.tokenSource = source
+	return client
+}
+
+type tokenSourceRefresher struct {
+	source oauth2.TokenSource
+}
+
+func (r tokenSourceRefresher) RefreshToken(string) (*uaa.Tokens, error) {
+	token, err := r.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := uaa.TokensFromOAuth2(token)
+	return &tokens, nil
+}
+
+func (c *RefresherClient) syncTokenSource() error {
+	if c.tokenSource == nil {
+		return nil
+	}
+
+	token, err := c.tokenSource.Token()
+	if err != nil {
+		return err
+	}
+
+	c.setTokens(uaa.TokensFromOAuth2(token))
+	return nil
+}
@@ -0,0 +1,109 @@
+package cf_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"golang.org/x/oauth2"
+
+	"github.com/tscolari/cfapi/cf"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type staticTokenSource struct {
+	tokens []string
+	err    error
+	calls  int
+}
+
+func (s *staticTokenSource) Token() (*oauth2.Token, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	token := s.tokens[s.calls]
+	if s.calls < len(s.tokens)-1 {
+		s.calls++
+	}
+	return &oauth2.Token{AccessToken: token}, nil
+}
+
+var _ = Describe("NewTokenSourceClient", func() {
+	var server *httptest.Server
+	var source *staticTokenSource
+	var authorizations []string
+	var authorizationsMutex sync.Mutex
+
+	BeforeEach(func() {
+		authorizations = nil
+		source = &staticTokenSource{tokens: []string{"first-token", "second-token"}}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizationsMutex.Lock()
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+			authorizationsMutex.Unlock()
+			if r.Header.Get("Authorization") != "bearer second-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"name":"my-app"}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("authenticates requests with the token source and retries on unauthorized", func() {
+		client := cf.NewTokenSourceClient(server.URL, source)
+
+		var app struct {
+			Name string `json:"name"`
+		}
+		err := client.Get("/v2/apps/123", &app)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(app.Name).To(Equal("my-app"))
+
+		Expect(authorizations).To(Equal([]string{"bearer first-token", "bearer second-token"}))
+		Expect(client.CurrentTokens().AccessToken).To(Equal("second-token"))
+	})
+
+	It("doesn't retry when the token source hands out the same token", func() {
+		source.tokens = []string{"first-token"}
+		client := cf.NewTokenSourceClient(server.URL, source)
+
+		err := client.Get("/v2/apps/123", nil)
+		Expect(err).To(MatchError("Unauthorized"))
+		Expect(authorizations).To(Equal([]string{"bearer first-token"}))
+	})
+
+	It("can be used concurrently", func() {
+		client := cf.NewTokenSourceClient(server.URL, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "second-token"}))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer GinkgoRecover()
+				Expect(client.Get("/v2/apps/123", nil)).To(Succeed())
+				Expect(client.CurrentTokens().AccessToken).To(Equal("second-token"))
+			}()
+		}
+		wg.Wait()
+
+		Expect(authorizations).To(HaveLen(10))
+	})
+
+	It("returns token source errors", func() {
+		source.err = errors.New("no token for you")
+		client := cf.NewTokenSourceClient(server.URL, source)
+
+		err := client.Get("/v2/apps/123", nil)
+		Expect(err).To(MatchError("no token for you"))
+		Expect(authorizations).To(BeEmpty())
+	})
+})
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Collector interface {
+	ObserveRequest(method, path string, statusCode int, duration time.Duration)
+	ObserveTokenRefresh(duration time.Duration, err error)
+}
+
+var (
+	guidSegment    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	numericSegment = regexp.MustCompile(`^[0-9]+$`)
+	revokedToken   = regexp.MustCompile(`/oauth/token/revoke/[^/]+$`)
+)
+
+func PathTemplate(path string) string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	path = revokedToken.ReplaceAllString(path, "/oauth/token/revoke/:id")
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case guidSegment.MatchString(segment):
+			segments[i] = ":guid"
+		case numericSegment.MatchString(segment):
+			segments[i] = ":id"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
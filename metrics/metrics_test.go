@@ -0,0 +1,27 @@
+package metrics_test
+
+import (
+	"github.com/tscolari/cfapi/metrics"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PathTemplate", func() {
+	It("replaces guids and numeric ids", func() {
+		Expect(metrics.PathTemplate("/v2/apps/49934910-756a-46c5-bae1-b82540e28937/instances/3")).To(Equal("/v2/apps/:guid/instances/:id"))
+	})
+
+	It("drops the query string", func() {
+		Expect(metrics.PathTemplate("/v2/apps?q=name:foo&page=2")).To(Equal("/v2/apps"))
+	})
+
+	It("hides revoked token ids", func() {
+		Expect(metrics.PathTemplate("/oauth/token/revoke/0f2e4c3c9b8a4e1f")).To(Equal("/oauth/token/revoke/:id"))
+		Expect(metrics.PathTemplate("/oauth/token/revoke/user/49934910-756a-46c5-bae1-b82540e28937")).To(Equal("/oauth/token/revoke/user/:guid"))
+	})
+
+	It("keeps static paths untouched", func() {
+		Expect(metrics.PathTemplate("/oauth/token")).To(Equal("/oauth/token"))
+	})
+})
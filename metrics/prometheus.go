@@ -0,0 +1,195 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type requestKey struct {
+	method string
+	path   string
+	code   string
+}
+
+type latencyKey struct {
+	method string
+	path   string
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type PrometheusCollector struct {
+	namespace       string
+	buckets         []float64
+	mutex           sync.Mutex
+	requests        map[requestKey]uint64
+	latencies       map[latencyKey]*histogram
+	refreshes       uint64
+	refreshFailures uint64
+	refreshLatency  *histogram
+}
+
+func NewPrometheusCollector(namespace string) *PrometheusCollector {
+	if namespace == "" {
+		namespace = "cfapi"
+	}
+
+	return &PrometheusCollector{
+		namespace:      namespace,
+		buckets:        DefaultBuckets,
+		requests:       map[requestKey]uint64{},
+		latencies:      map[latencyKey]*histogram{},
+		refreshLatency: newHistogram(DefaultBuckets),
+	}
+}
+
+func (c *PrometheusCollector) ObserveRequest(method, path string, statusCode int, duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	code := "error"
+	if statusCode > 0 {
+		code = strconv.Itoa(statusCode)
+	}
+
+	template := PathTemplate(path)
+	c.requests[requestKey{method: method, path: template, code: code}]++
+
+	key := latencyKey{method: method, path: template}
+	if _, ok := c.latencies[key]; !ok {
+		c.latencies[key] = newHistogram(c.buckets)
+	}
+	c.latencies[key].observe(c.buckets, duration.Seconds())
+}
+
+func (c *PrometheusCollector) ObserveTokenRefresh(duration time.Duration, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.refreshes++
+	if err != nil {
+		c.refreshFailures++
+	}
+	c.refreshLatency.observe(c.buckets, duration.Seconds())
+}
+
+func (c *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+func (c *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	buffer := new(bytes.Buffer)
+
+	requestsName := c.namespace + "_requests_total"
+	fmt.Fprintf(buffer, "# HELP %s Total number of API requests.\n", requestsName)
+	fmt.Fprintf(buffer, "# TYPE %s counter\n", requestsName)
+	requestKeys := make([]requestKey, 0, len(c.requests))
+	for key := range c.requests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		a, b := requestKeys[i], requestKeys[j]
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.code < b.code
+	})
+	for _, key := range requestKeys {
+		fmt.Fprintf(buffer, "%s{%s} %d\n", requestsName, labels("method", key.method, "path", key.path, "code", key.code), c.requests[key])
+	}
+
+	latencyName := c.namespace + "_request_duration_seconds"
+	fmt.Fprintf(buffer, "# HELP %s API request latencies in seconds.\n", latencyName)
+	fmt.Fprintf(buffer, "# TYPE %s histogram\n", latencyName)
+	latencyKeys := make([]latencyKey, 0, len(c.latencies))
+	for key := range c.latencies {
+		latencyKeys = append(latencyKeys, key)
+	}
+	sort.Slice(latencyKeys, func(i, j int) bool {
+		a, b := latencyKeys[i], latencyKeys[j]
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		return a.method < b.method
+	})
+	for _, key := range latencyKeys {
+		c.latencies[key].write(buffer, latencyName, c.buckets, "method", key.method, "path", key.path)
+	}
+
+	refreshesName := c.namespace + "_token_refreshes_total"
+	fmt.Fprintf(buffer, "# HELP %s Total number of token refresh attempts.\n", refreshesName)
+	fmt.Fprintf(buffer, "# TYPE %s counter\n", refreshesName)
+	fmt.Fprintf(buffer, "%s %d\n", refreshesName, c.refreshes)
+
+	failuresName := c.namespace + "_token_refresh_failures_total"
+	fmt.Fprintf(buffer, "# HELP %s Total number of failed token refreshes.\n", failuresName)
+	fmt.Fprintf(buffer, "# TYPE %s counter\n", failuresName)
+	fmt.Fprintf(buffer, "%s %d\n", failuresName, c.refreshFailures)
+
+	refreshLatencyName := c.namespace + "_token_refresh_duration_seconds"
+	fmt.Fprintf(buffer, "# HELP %s Token refresh latencies in seconds.\n", refreshLatencyName)
+	fmt.Fprintf(buffer, "# TYPE %s histogram\n", refreshLatencyName)
+	c.refreshLatency.write(buffer, refreshLatencyName, c.buckets)
+
+	return buffer.WriteTo(w)
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(buckets []float64, value float64) {
+	for i, bound := range buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+func (h *histogram) write(w io.Writer, name string, buckets []float64, labelPairs ...string) {
+	for i, bound := range buckets {
+		bucketLabels := append(append([]string{}, labelPairs...), "le", strconv.FormatFloat(bound, 'g', -1, 64))
+		fmt.Fprintf(w, "%s_bucket{%s} %d\n", name, labels(bucketLabels...), h.counts[i])
+	}
+	infLabels := append(append([]string{}, labelPairs...), "le", "+Inf")
+	fmt.Fprintf(w, "%s_bucket{%s} %d\n", name, labels(infLabels...), h.count)
+
+	suffix := ""
+	if len(labelPairs) > 0 {
+		suffix = "{" + labels(labelPairs...) + "}"
+	}
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, suffix, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, suffix, h.count)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labels(pairs ...string) string {
+	formatted := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		formatted = append(formatted, fmt.Sprintf(`%s="%s"`, pairs[i], labelEscaper.Replace(pairs[i+1])))
+	}
+	return strings.Join(formatted, ",")
+}
@@ -0,0 +1,80 @@
+package metrics_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/tscolari/cfapi/metrics"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrometheusCollector", func() {
+	var collector *metrics.PrometheusCollector
+
+	BeforeEach(func() {
+		collector = metrics.NewPrometheusCollector("")
+	})
+
+	render := func() string {
+		output := new(bytes.Buffer)
+		_, err := collector.WriteTo(output)
+		Expect(err).ToNot(HaveOccurred())
+		return output.String()
+	}
+
+	It("counts requests by method, path template and status code", func() {
+		collector.ObserveRequest("GET", "/v2/apps/49934910-756a-46c5-bae1-b82540e28937", 200, 20*time.Millisecond)
+		collector.ObserveRequest("GET", "/v2/apps/81be5fa3-c7ef-490f-b176-7c9079c0ff83", 200, 20*time.Millisecond)
+		collector.ObserveRequest("GET", "/v2/apps/81be5fa3-c7ef-490f-b176-7c9079c0ff83", 401, 20*time.Millisecond)
+		collector.ObserveRequest("POST", "/v2/apps", 0, time.Second)
+
+		output := render()
+		Expect(output).To(ContainSubstring("# TYPE cfapi_requests_total counter\n"))
+		Expect(output).To(ContainSubstring(`cfapi_requests_total{method="GET",path="/v2/apps/:guid",code="200"} 2` + "\n"))
+		Expect(output).To(ContainSubstring(`cfapi_requests_total{method="GET",path="/v2/apps/:guid",code="401"} 1` + "\n"))
+		Expect(output).To(ContainSubstring(`cfapi_requests_total{method="POST",path="/v2/apps",code="error"} 1` + "\n"))
+	})
+
+	It("records request latencies as histograms", func() {
+		collector.ObserveRequest("GET", "/v2/apps", 200, 20*time.Millisecond)
+		collector.ObserveRequest("GET", "/v2/apps", 200, 2*time.Second)
+
+		output := render()
+		Expect(output).To(ContainSubstring("# TYPE cfapi_request_duration_seconds histogram\n"))
+		Expect(output).To(ContainSubstring(`cfapi_request_duration_seconds_bucket{method="GET",path="/v2/apps",le="0.01"} 0` + "\n"))
+		Expect(output).To(ContainSubstring(`cfapi_request_duration_seconds_bucket{method="GET",path="/v2/apps",le="0.025"} 1` + "\n"))
+		Expect(output).To(ContainSubstring(`cfapi_request_duration_seconds_bucket{method="GET",path="/v2/apps",le="2.5"} 2` + "\n"))
+		Expect(output).To(ContainSubstring(`cfapi_request_duration_seconds_bucket{method="GET",path="/v2/apps",le="+Inf"} 2` + "\n"))
+		Expect(output).To(ContainSubstring(`cfapi_request_duration_seconds_sum{method="GET",path="/v2/apps"} 2.02` + "\n"))
+		Expect(output).To(ContainSubstring(`cfapi_request_duration_seconds_count{method="GET",path="/v2/apps"} 2` + "\n"))
+	})
+
+	It("counts token refreshes and failures", func() {
+		collector.ObserveTokenRefresh(time.Millisecond, nil)
+		collector.ObserveTokenRefresh(time.Millisecond, errors.New("invalid_grant"))
+
+		output := render()
+		Expect(output).To(ContainSubstring("cfapi_token_refreshes_total 2\n"))
+		Expect(output).To(ContainSubstring("cfapi_token_refresh_failures_total 1\n"))
+		Expect(output).To(ContainSubstring("cfapi_token_refresh_duration_seconds_count 2\n"))
+	})
+
+	It("uses the given namespace", func() {
+		collector = metrics.NewPrometheusCollector("myapp")
+		Expect(render()).To(ContainSubstring("myapp_token_refreshes_total 0\n"))
+	})
+
+	It("serves the metrics over http", func() {
+		collector.ObserveTokenRefresh(time.Millisecond, nil)
+
+		recorder := httptest.NewRecorder()
+		collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+		Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("text/plain; version=0.0.4"))
+		Expect(recorder.Body.String()).To(ContainSubstring("cfapi_token_refreshes_total 1\n"))
+	})
+})
@@ -0,0 +1,147 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/tscolari/cfapi/telemetry"
+)
+
+type FakeSpan struct {
+	EndStub        func()
+	endMutex       sync.RWMutex
+	endArgsForCall []struct {
+	}
+	RecordErrorStub        func(error)
+	recordErrorMutex       sync.RWMutex
+	recordErrorArgsForCall []struct {
+		arg1 error
+	}
+	SetAttributeStub        func(string, interface{})
+	setAttributeMutex       sync.RWMutex
+	setAttributeArgsForCall []struct {
+		arg1 string
+		arg2 interface{}
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSpan) End() {
+	fake.endMutex.Lock()
+	fake.endArgsForCall = append(fake.endArgsForCall, struct {
+	}{})
+	stub := fake.EndStub
+	fake.recordInvocation("End", []interface{}{})
+	fake.endMutex.Unlock()
+	if stub != nil {
+		fake.EndStub()
+	}
+}
+
+func (fake *FakeSpan) EndCallCount() int {
+	fake.endMutex.RLock()
+	defer fake.endMutex.RUnlock()
+	return len(fake.endArgsForCall)
+}
+
+func (fake *FakeSpan) EndCalls(stub func()) {
+	fake.endMutex.Lock()
+	defer fake.endMutex.Unlock()
+	fake.EndStub = stub
+}
+
+func (fake *FakeSpan) RecordError(arg1 error) {
+	fake.recordErrorMutex.Lock()
+	fake.recordErrorArgsForCall = append(fake.recordErrorArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.RecordErrorStub
+	fake.recordInvocation("RecordError", []interface{}{arg1})
+	fake.recordErrorMutex.Unlock()
+	if stub != nil {
+		fake.RecordErrorStub(arg1)
+	}
+}
+
+func (fake *FakeSpan) RecordErrorCallCount() int {
+	fake.recordErrorMutex.RLock()
+	defer fake.recordErrorMutex.RUnlock()
+	return len(fake.recordErrorArgsForCall)
+}
+
+func (fake *FakeSpan) RecordErrorCalls(stub func(error)) {
+	fake.recordErrorMutex.Lock()
+	defer fake.recordErrorMutex.Unlock()
+	fake.RecordErrorStub = stub
+}
+
+func (fake *FakeSpan) RecordErrorArgsForCall(i int) error {
+	fake.recordErrorMutex.RLock()
+	defer fake.recordErrorMutex.RUnlock()
+	argsForCall := fake.recordErrorArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSpan) SetAttribute(arg1 string, arg2 interface{}) {
+	fake.setAttributeMutex.Lock()
+	fake.setAttributeArgsForCall = append(fake.setAttributeArgsForCall, struct {
+		arg1 string
+		arg2 interface{}
+	}{arg1, arg2})
+	stub := fake.SetAttributeStub
+	fake.recordInvocation("SetAttribute", []interface{}{arg1, arg2})
+	fake.setAttributeMutex.Unlock()
+	if stub != nil {
+		fake.SetAttributeStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeSpan) SetAttributeCallCount() int {
+	fake.setAttributeMutex.RLock()
+	defer fake.setAttributeMutex.RUnlock()
+	return len(fake.setAttributeArgsForCall)
+}
+
+func (fake *FakeSpan) SetAttributeCalls(stub func(string, interface{})) {
+	fake.setAttributeMutex.Lock()
+	defer fake.setAttributeMutex.Unlock()
+	fake.SetAttributeStub = stub
+}
+
+func (fake *FakeSpan) SetAttributeArgsForCall(i int) (string, interface{}) {
+	fake.setAttributeMutex.RLock()
+	defer fake.setAttributeMutex.RUnlock()
+	argsForCall := fake.setAttributeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSpan) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.endMutex.RLock()
+	defer fake.endMutex.RUnlock()
+	fake.recordErrorMutex.RLock()
+	defer fake.recordErrorMutex.RUnlock()
+	fake.setAttributeMutex.RLock()
+	defer fake.setAttributeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSpan) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ telemetry.Span = new(FakeSpan)
@@ -0,0 +1,161 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/tscolari/cfapi/telemetry"
+)
+
+type FakeTracer struct {
+	InjectStub        func(context.Context, http.Header)
+	injectMutex       sync.RWMutex
+	injectArgsForCall []struct {
+		arg1 context.Context
+		arg2 http.Header
+	}
+	StartStub        func(context.Context, string) (context.Context, telemetry.Span)
+	startMutex       sync.RWMutex
+	startArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	startReturns struct {
+		result1 context.Context
+		result2 telemetry.Span
+	}
+	startReturnsOnCall map[int]struct {
+		result1 context.Context
+		result2 telemetry.Span
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTracer) Inject(arg1 context.Context, arg2 http.Header) {
+	fake.injectMutex.Lock()
+	fake.injectArgsForCall = append(fake.injectArgsForCall, struct {
+		arg1 context.Context
+		arg2 http.Header
+	}{arg1, arg2})
+	stub := fake.InjectStub
+	fake.recordInvocation("Inject", []interface{}{arg1, arg2})
+	fake.injectMutex.Unlock()
+	if stub != nil {
+		fake.InjectStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeTracer) InjectCallCount() int {
+	fake.injectMutex.RLock()
+	defer fake.injectMutex.RUnlock()
+	return len(fake.injectArgsForCall)
+}
+
+func (fake *FakeTracer) InjectCalls(stub func(context.Context, http.Header)) {
+	fake.injectMutex.Lock()
+	defer fake.injectMutex.Unlock()
+	fake.InjectStub = stub
+}
+
+func (fake *FakeTracer) InjectArgsForCall(i int) (context.Context, http.Header) {
+	fake.injectMutex.RLock()
+	defer fake.injectMutex.RUnlock()
+	argsForCall := fake.injectArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTracer) Start(arg1 context.Context, arg2 string) (context.Context, telemetry.Span) {
+	fake.startMutex.Lock()
+	ret, specificReturn := fake.startReturnsOnCall[len(fake.startArgsForCall)]
+	fake.startArgsForCall = append(fake.startArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.StartStub
+	fakeReturns := fake.startReturns
+	fake.recordInvocation("Start", []interface{}{arg1, arg2})
+	fake.startMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTracer) StartCallCount() int {
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	return len(fake.startArgsForCall)
+}
+
+func (fake *FakeTracer) StartCalls(stub func(context.Context, string) (context.Context, telemetry.Span)) {
+	fake.startMutex.Lock()
+	defer fake.startMutex.Unlock()
+	fake.StartStub = stub
+}
+
+func (fake *FakeTracer) StartArgsForCall(i int) (context.Context, string) {
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	argsForCall := fake.startArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTracer) StartReturns(result1 context.Context, result2 telemetry.Span) {
+	fake.startMutex.Lock()
+	defer fake.startMutex.Unlock()
+	fake.StartStub = nil
+	fake.startReturns = struct {
+		result1 context.Context
+		result2 telemetry.Span
+	}{result1, result2}
+}
+
+func (fake *FakeTracer) StartReturnsOnCall(i int, result1 context.Context, result2 telemetry.Span) {
+	fake.startMutex.Lock()
+	defer fake.startMutex.Unlock()
+	fake.StartStub = nil
+	if fake.startReturnsOnCall == nil {
+		fake.startReturnsOnCall = make(map[int]struct {
+			result1 context.Context
+			result2 telemetry.Span
+		})
+	}
+	fake.startReturnsOnCall[i] = struct {
+		result1 context.Context
+		result2 telemetry.Span
+	}{result1, result2}
+}
+
+func (fake *FakeTracer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.injectMutex.RLock()
+	defer fake.injectMutex.RUnlock()
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeTracer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ telemetry.Tracer = new(FakeTracer)
@@ -0,0 +1,72 @@
+package otelbridge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tscolari/cfapi/telemetry"
+)
+
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{
+		tracer:     tracer,
+		propagator: propagation.TraceContext{},
+	}
+}
+
+func NewTracerWithPropagator(tracer trace.Tracer, propagator propagation.TextMapPropagator) *Tracer {
+	return &Tracer{
+		tracer:     tracer,
+		propagator: propagator,
+	}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, telemetry.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, &Span{span: span}
+}
+
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+type Span struct {
+	span trace.Span
+}
+
+func (s *Span) SetAttribute(key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		s.span.SetAttributes(attribute.String(key, v))
+	case int:
+		s.span.SetAttributes(attribute.Int(key, v))
+	case int64:
+		s.span.SetAttributes(attribute.Int64(key, v))
+	case bool:
+		s.span.SetAttributes(attribute.Bool(key, v))
+	case float64:
+		s.span.SetAttributes(attribute.Float64(key, v))
+	default:
+		s.span.SetAttributes(attribute.String(key, fmt.Sprint(v)))
+	}
+}
+
+func (s *Span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *Span) End() {
+	s.span.End()
+}
@@ -0,0 +1,13 @@
+package otelbridge_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestOtelbridge(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Otelbridge Suite")
+}
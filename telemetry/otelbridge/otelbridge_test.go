@@ -0,0 +1,62 @@
+package otelbridge_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tscolari/cfapi/telemetry"
+	"github.com/tscolari/cfapi/telemetry/otelbridge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracer", func() {
+	var (
+		recorder *tracetest.SpanRecorder
+		tracer   *otelbridge.Tracer
+	)
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		tracer = otelbridge.NewTracer(provider.Tracer("cfapi"))
+	})
+
+	It("records spans with attributes and errors", func() {
+		_, span := telemetry.StartSpan(tracer, context.Background(), "cf GET /v2/apps")
+		span.SetAttribute("http.method", "GET")
+		span.SetAttribute("http.status_code", 500)
+		telemetry.EndSpan(span, errors.New("Internal Server Error"))
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name()).To(Equal("cf GET /v2/apps"))
+		Expect(spans[0].Attributes()).To(ContainElement(attribute.String("http.method", "GET")))
+		Expect(spans[0].Attributes()).To(ContainElement(attribute.Int("http.status_code", 500)))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+	})
+
+	It("injects the W3C traceparent of the current span", func() {
+		ctx, span := tracer.Start(context.Background(), "cf GET /v2/apps")
+		defer span.End()
+
+		header := http.Header{}
+		tracer.Inject(ctx, header)
+
+		Expect(header.Get("traceparent")).ToNot(BeEmpty())
+
+		extracted := propagation.TraceContext{}.Extract(context.Background(), propagation.HeaderCarrier(header))
+		spanContext := trace.SpanContextFromContext(extracted)
+		Expect(spanContext.IsValid()).To(BeTrue())
+		Expect(spanContext.IsSampled()).To(BeTrue())
+	})
+})
@@ -0,0 +1,46 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+)
+
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+	Inject(ctx context.Context, header http.Header)
+}
+
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+func StartSpan(tracer Tracer, ctx context.Context, name string) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return tracer.Start(ctx, name)
+}
+
+func Inject(tracer Tracer, ctx context.Context, header http.Header) {
+	if tracer == nil {
+		return
+	}
+
+	tracer.Inject(ctx, header)
+}
+
+func EndSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) RecordError(err error)                      {}
+func (noopSpan) End()                                       {}
@@ -0,0 +1,68 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/tscolari/cfapi/telemetry"
+	"github.com/tscolari/cfapi/telemetry/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Telemetry", func() {
+	Context("without a tracer", func() {
+		It("starts no-op spans and keeps the context", func() {
+			ctx := context.WithValue(context.Background(), "key", "value")
+			spanCtx, span := telemetry.StartSpan(nil, ctx, "some-span")
+			Expect(spanCtx).To(Equal(ctx))
+
+			Expect(func() {
+				span.SetAttribute("key", "value")
+				telemetry.EndSpan(span, errors.New("failed"))
+			}).ToNot(Panic())
+		})
+
+		It("doesn't inject any headers", func() {
+			header := http.Header{}
+			telemetry.Inject(nil, context.Background(), header)
+			Expect(header).To(BeEmpty())
+		})
+	})
+
+	Context("with a tracer", func() {
+		var (
+			tracer *fakes.FakeTracer
+			span   *fakes.FakeSpan
+		)
+
+		BeforeEach(func() {
+			tracer = new(fakes.FakeTracer)
+			span = new(fakes.FakeSpan)
+			tracer.StartReturns(context.Background(), span)
+		})
+
+		It("delegates span creation", func() {
+			_, started := telemetry.StartSpan(tracer, context.Background(), "some-span")
+			Expect(started).To(Equal(span))
+
+			_, name := tracer.StartArgsForCall(0)
+			Expect(name).To(Equal("some-span"))
+		})
+
+		It("records errors when ending spans", func() {
+			telemetry.EndSpan(span, errors.New("failed"))
+			Expect(span.RecordErrorCallCount()).To(Equal(1))
+			Expect(span.RecordErrorArgsForCall(0)).To(MatchError("failed"))
+			Expect(span.EndCallCount()).To(Equal(1))
+		})
+
+		It("delegates header injection", func() {
+			header := http.Header{}
+			telemetry.Inject(tracer, context.Background(), header)
+			Expect(tracer.InjectCallCount()).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package trace
+
+import "regexp"
+
+const PrivateDataPlaceholder = "[PRIVATE DATA HIDDEN]"
+
+var (
+	authorizationHeader = regexp.MustCompile(`(?im)^((?:proxy-)?authorization):[ \t]*(?:(\w+)[ \t]+)?[^\r\n]*`)
+	formSecrets         = regexp.MustCompile(`(^|[?&\s])(password|passcode|refresh_token|access_token|id_token|client_secret|code|code_verifier|assertion)=[^&\s]*`)
+	revokedToken        = regexp.MustCompile(`(/oauth/token/revoke/)[^/\s?#]+([\s?#]|$)`)
+	jsonSecrets         = regexp.MustCompile(`"(password|oldPassword|new_password|passcode|refresh_token|access_token|id_token|client_secret|secret|oldSecret|code|code_verifier|assertion|signingKey|signingCert|privateKey|privateKeyPassword|passphrase|certificate|key)"(\s*):(\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+func Sanitize(input string) string {
+	sanitized := authorizationHeader.ReplaceAllStringFunc(input, func(header string) string {
+		match := authorizationHeader.FindStringSubmatch(header)
+		if match[2] == "" {
+			return match[1] + ": " + PrivateDataPlaceholder
+		}
+		return match[1] + ": " + match[2] + " " + PrivateDataPlaceholder
+	})
+	sanitized = formSecrets.ReplaceAllString(sanitized, "$1$2="+PrivateDataPlaceholder)
+	sanitized = revokedToken.ReplaceAllString(sanitized, "${1}"+PrivateDataPlaceholder+"${2}")
+	sanitized = jsonSecrets.ReplaceAllString(sanitized, `"$1"$2:$3"`+PrivateDataPlaceholder+`"`)
+	return sanitized
+}
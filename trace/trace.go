@@ -0,0 +1,57 @@
+package trace
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"time"
+)
+
+type Printer interface {
+	Printf(format string, v ...interface{})
+}
+
+type writerPrinter struct {
+	writer io.Writer
+}
+
+func NewWriterPrinter(writer io.Writer) Printer {
+	return &writerPrinter{writer: writer}
+}
+
+func (p *writerPrinter) Printf(format string, v ...interface{}) {
+	fmt.Fprintf(p.writer, format, v...)
+}
+
+func DumpRequest(printer Printer, request *http.Request) {
+	if printer == nil {
+		return
+	}
+
+	dump, err := httputil.DumpRequestOut(request, true)
+	if err != nil {
+		printer.Printf("\nREQUEST: [%s] %s %s\nFailed to dump request: %s\n", timestamp(), request.Method, Sanitize(request.URL.String()), err.Error())
+		return
+	}
+
+	printer.Printf("\nREQUEST: [%s]\n%s\n", timestamp(), Sanitize(string(dump)))
+}
+
+func DumpResponse(printer Printer, response *http.Response) {
+	if printer == nil {
+		return
+	}
+
+	dump, err := httputil.DumpResponse(response, true)
+	if err != nil {
+		printer.Printf("\nRESPONSE: [%s] %s\nFailed to dump response: %s\n", timestamp(), response.Status, err.Error())
+		return
+	}
+
+	printer.Printf("\nRESPONSE: [%s]\n%s\n", timestamp(), Sanitize(string(dump)))
+}
+
+func timestamp() string {
+	return time.Now().Format(time.RFC3339)
+}
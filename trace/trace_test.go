@@ -0,0 +1,93 @@
+package trace_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/tscolari/cfapi/trace"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trace", func() {
+	var (
+		output  *bytes.Buffer
+		printer trace.Printer
+	)
+
+	BeforeEach(func() {
+		output = new(bytes.Buffer)
+		printer = trace.NewWriterPrinter(output)
+	})
+
+	Describe("DumpRequest", func() {
+		It("prints the method, url, headers and body", func() {
+			request, err := http.NewRequest("POST", "http://uaa.example.com/oauth/token", strings.NewReader("grant_type=password&password=secret"))
+			Expect(err).ToNot(HaveOccurred())
+			request.Header.Set("Authorization", "Basic Y2Y6")
+
+			trace.DumpRequest(printer, request)
+
+			Expect(output.String()).To(ContainSubstring("REQUEST:"))
+			Expect(output.String()).To(ContainSubstring("POST /oauth/token HTTP/1.1"))
+			Expect(output.String()).To(ContainSubstring("Host: uaa.example.com"))
+			Expect(output.String()).To(ContainSubstring("Authorization: Basic [PRIVATE DATA HIDDEN]"))
+			Expect(output.String()).To(ContainSubstring("grant_type=password&password=[PRIVATE DATA HIDDEN]"))
+		})
+
+		It("keeps the request body readable", func() {
+			request, err := http.NewRequest("POST", "http://uaa.example.com/oauth/token", strings.NewReader("some-body"))
+			Expect(err).ToNot(HaveOccurred())
+
+			trace.DumpRequest(printer, request)
+
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(Equal("some-body"))
+		})
+
+		It("accepts a standard logger", func() {
+			request, err := http.NewRequest("GET", "http://api.example.com/v2/apps", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			trace.DumpRequest(log.New(output, "", 0), request)
+			Expect(output.String()).To(ContainSubstring("GET /v2/apps HTTP/1.1"))
+		})
+
+		It("does nothing without a printer", func() {
+			request, err := http.NewRequest("GET", "http://api.example.com/v2/apps", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(func() { trace.DumpRequest(nil, request) }).ToNot(Panic())
+		})
+	})
+
+	Describe("DumpResponse", func() {
+		It("prints the status, headers and body", func() {
+			response := &http.Response{
+				Status:     "200 OK",
+				StatusCode: 200,
+				Proto:      "HTTP/1.1",
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"access_token":"1234","token_type":"bearer"}`)),
+			}
+
+			trace.DumpResponse(printer, response)
+
+			Expect(output.String()).To(ContainSubstring("RESPONSE:"))
+			Expect(output.String()).To(ContainSubstring("HTTP/1.1 200 OK"))
+			Expect(output.String()).To(ContainSubstring("Content-Type: application/json"))
+			Expect(output.String()).To(ContainSubstring(`{"access_token":"[PRIVATE DATA HIDDEN]","token_type":"bearer"}`))
+
+			body, err := ioutil.ReadAll(response.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(Equal(`{"access_token":"1234","token_type":"bearer"}`))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package uaa
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+const (
+	JWTBearerGrantType   = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	SAML2BearerGrantType = "urn:ietf:params:oauth:grant-type:saml2-bearer"
+)
+
+func (c *Client) AuthenticateWithJWTBearer(assertion string, scopes ...string) (*Tokens, error) {
+	return c.AuthenticateWithJWTBearerWithContext(context.Background(), assertion, scopes...)
+}
+
+func (c *Client) AuthenticateWithJWTBearerWithContext(ctx context.Context, assertion string, scopes ...string) (*Tokens, error) {
+	return c.fetchToken(ctx, assertionGrant(JWTBearerGrantType, assertion, scopes))
+}
+
+func (c *Client) AuthenticateWithSAML2Bearer(assertion string, scopes ...string) (*Tokens, error) {
+	return c.AuthenticateWithSAML2BearerWithContext(context.Background(), assertion, scopes...)
+}
+
+func (c *Client) AuthenticateWithSAML2BearerWithContext(ctx context.Context, assertion string, scopes ...string) (*Tokens, error) {
+	return c.fetchToken(ctx, assertionGrant(SAML2BearerGrantType, assertion, scopes))
+}
+
+func assertionGrant(grantType, assertion string, scopes []string) url.Values {
+	data := url.Values{
+		"grant_type": {grantType},
+		"assertion":  {assertion},
+	}
+	if len(scopes) > 0 {
+		data.Set("scope", strings.Join(scopes, " "))
+	}
+
+	return data
+}
@@ -0,0 +1,95 @@
+package uaa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Assertion grants", func() {
+	var (
+		server   *httptest.Server
+		subject  uaa.Client
+		form     url.Values
+		username string
+		password string
+		hasBasic bool
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/oauth/token"))
+			Expect(r.ParseForm()).To(Succeed())
+			form = r.PostForm
+			username, password, hasBasic = r.BasicAuth()
+
+			w.Write([]byte(`{"access_token":"exchanged-access-token","refresh_token":"exchanged-refresh-token","token_type":"bearer"}`))
+		}))
+		subject = uaa.NewClient(server.URL)
+		subject.ClientID = "workload"
+		subject.ClientSecret = "workload-secret"
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("AuthenticateWithJWTBearer", func() {
+		It("exchanges the assertion for tokens", func() {
+			tokens, err := subject.AuthenticateWithJWTBearer("external-jwt", "openid", "cloud_controller.read")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokens.AccessToken).To(Equal("exchanged-access-token"))
+			Expect(tokens.RefreshToken).To(Equal("exchanged-refresh-token"))
+
+			Expect(form.Get("grant_type")).To(Equal("urn:ietf:params:oauth:grant-type:jwt-bearer"))
+			Expect(form.Get("assertion")).To(Equal("external-jwt"))
+			Expect(form.Get("scope")).To(Equal("openid cloud_controller.read"))
+
+			Expect(hasBasic).To(BeTrue())
+			Expect(username).To(Equal("workload"))
+			Expect(password).To(Equal("workload-secret"))
+			Expect(form.Get("client_secret")).To(BeEmpty())
+		})
+	})
+
+	Describe("AuthenticateWithSAML2Bearer", func() {
+		It("exchanges the assertion for tokens", func() {
+			tokens, err := subject.AuthenticateWithSAML2Bearer("PHNhbWw-YXNzZXJ0aW9u")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokens.AccessToken).To(Equal("exchanged-access-token"))
+
+			Expect(form.Get("grant_type")).To(Equal("urn:ietf:params:oauth:grant-type:saml2-bearer"))
+			Expect(form.Get("assertion")).To(Equal("PHNhbWw-YXNzZXJ0aW9u"))
+			Expect(form["scope"]).To(BeNil())
+		})
+	})
+
+	Describe("client authentication", func() {
+		It("can send the credentials in the body", func() {
+			subject.ClientAuthMethod = uaa.ClientAuthPost
+
+			_, err := subject.AuthenticateWithJWTBearer("external-jwt")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(hasBasic).To(BeFalse())
+			Expect(form.Get("client_id")).To(Equal("workload"))
+			Expect(form.Get("client_secret")).To(Equal("workload-secret"))
+		})
+
+		It("can authenticate as a public client", func() {
+			subject.ClientAuthMethod = uaa.ClientAuthNone
+
+			_, err := subject.AuthenticateWithJWTBearer("external-jwt")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(hasBasic).To(BeFalse())
+			Expect(form.Get("client_id")).To(Equal("workload"))
+			Expect(form["client_secret"]).To(BeNil())
+		})
+	})
+})
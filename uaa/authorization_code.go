@@ -0,0 +1,267 @@
+package uaa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type PKCE struct {
+	Verifier  string
+	Challenge string
+	Method    string
+}
+
+func NewPKCE() (PKCE, error) {
+	verifier, err := randomString(32)
+	if err != nil {
+		return PKCE{}, err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	return PKCE{
+		Verifier:  verifier,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge[:]),
+		Method:    "S256",
+	}, nil
+}
+
+type AuthorizationRequest struct {
+	RedirectURI string
+	Scopes      []string
+	State       string
+	PKCE        PKCE
+}
+
+func (c *Client) AuthorizeURL(request AuthorizationRequest) string {
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {c.ClientID},
+	}
+	if request.RedirectURI != "" {
+		query.Set("redirect_uri", request.RedirectURI)
+	}
+	if len(request.Scopes) > 0 {
+		query.Set("scope", strings.Join(request.Scopes, " "))
+	}
+	if request.State != "" {
+		query.Set("state", request.State)
+	}
+	if request.PKCE.Challenge != "" {
+		query.Set("code_challenge", request.PKCE.Challenge)
+		query.Set("code_challenge_method", request.PKCE.Method)
+	}
+
+	return c.endpoint + "/oauth/authorize?" + query.Encode()
+}
+
+func (c *Client) ExchangeCode(code, redirectURI, codeVerifier string) (*Tokens, error) {
+	return c.ExchangeCodeWithContext(context.Background(), code, redirectURI, codeVerifier)
+}
+
+func (c *Client) ExchangeCodeWithContext(ctx context.Context, code, redirectURI, codeVerifier string) (*Tokens, error) {
+	data := url.Values{
+		"grant_type": {"authorization_code"},
+		"code":       {code},
+		"client_id":  {c.ClientID},
+	}
+	if redirectURI != "" {
+		data.Set("redirect_uri", redirectURI)
+	}
+	if codeVerifier != "" {
+		data.Set("code_verifier", codeVerifier)
+	}
+
+	return c.fetchToken(ctx, data)
+}
+
+type AuthorizationCodeOptions struct {
+	Scopes []string
+
+	// Loopback mode: a temporary listener on ListenAddress receives the
+	// redirect after OpenBrowser sends the user to the authorize URL.
+	ListenAddress string
+	CallbackPath  string
+	OpenBrowser   func(authorizeURL string) error
+
+	// Headless mode: when PromptCode is set no listener is started and the
+	// user pastes the code (or the whole redirect URL) back.
+	RedirectURI string
+	PromptCode  func(authorizeURL string) (string, error)
+}
+
+func (c *Client) AuthenticateWithAuthorizationCode(options AuthorizationCodeOptions) (*Tokens, error) {
+	return c.AuthenticateWithAuthorizationCodeWithContext(context.Background(), options)
+}
+
+func (c *Client) AuthenticateWithAuthorizationCodeWithContext(ctx context.Context, options AuthorizationCodeOptions) (*Tokens, error) {
+	pkce, err := NewPKCE()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.PromptCode != nil {
+		return c.headlessAuthorizationCode(ctx, options, pkce, state)
+	}
+
+	return c.loopbackAuthorizationCode(ctx, options, pkce, state)
+}
+
+func (c *Client) headlessAuthorizationCode(ctx context.Context, options AuthorizationCodeOptions, pkce PKCE, state string) (*Tokens, error) {
+	authorizeURL := c.AuthorizeURL(AuthorizationRequest{
+		RedirectURI: options.RedirectURI,
+		Scopes:      options.Scopes,
+		State:       state,
+		PKCE:        pkce,
+	})
+
+	input, err := options.PromptCode(authorizeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := parsePastedCode(strings.TrimSpace(input), state)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ExchangeCodeWithContext(ctx, code, options.RedirectURI, pkce.Verifier)
+}
+
+func (c *Client) loopbackAuthorizationCode(ctx context.Context, options AuthorizationCodeOptions, pkce PKCE, state string) (*Tokens, error) {
+	if options.OpenBrowser == nil {
+		return nil, errors.New("OpenBrowser is required without PromptCode")
+	}
+
+	listenAddress := options.ListenAddress
+	if listenAddress == "" {
+		listenAddress = "127.0.0.1:0"
+	}
+	callbackPath := options.CallbackPath
+	if callbackPath == "" {
+		callbackPath = "/callback"
+	}
+
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to start callback listener: %s", err.Error())
+	}
+	defer listener.Close()
+
+	redirectURI := fmt.Sprintf("http://%s%s", listener.Addr().String(), callbackPath)
+	results := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		code, err := parseCallback(query, state)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			// Only a response carrying our state ends the flow, requests with
+			// a wrong or missing state are turned away even if they hold an
+			// error.
+			if query.Get("state") != state || query.Get("error") == "" {
+				return
+			}
+		} else {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html><body>Login successful, you can close this window.</body></html>"))
+		}
+
+		select {
+		case results <- callbackResult{code: code, err: err}:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authorizeURL := c.AuthorizeURL(AuthorizationRequest{
+		RedirectURI: redirectURI,
+		Scopes:      options.Scopes,
+		State:       state,
+		PKCE:        pkce,
+	})
+
+	err = options.OpenBrowser(authorizeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return c.ExchangeCodeWithContext(ctx, result.code, redirectURI, pkce.Verifier)
+	}
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+func parseCallback(query url.Values, state string) (string, error) {
+	if query.Get("state") != state {
+		return "", errors.New("Invalid authorization state")
+	}
+
+	if errorCode := query.Get("error"); errorCode != "" {
+		return "", &Error{ErrorCode: errorCode, Description: query.Get("error_description")}
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return "", errors.New("Missing authorization code")
+	}
+
+	return code, nil
+}
+
+func parsePastedCode(input, state string) (string, error) {
+	if !strings.Contains(input, "code=") && !strings.Contains(input, "error=") {
+		if input == "" {
+			return "", errors.New("Missing authorization code")
+		}
+		return input, nil
+	}
+
+	rawQuery := input
+	if parsed, err := url.Parse(input); err == nil && parsed.RawQuery != "" {
+		rawQuery = parsed.RawQuery
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("Invalid authorization response: %s", err.Error())
+	}
+
+	return parseCallback(query, state)
+}
+
+func randomString(size int) (string, error) {
+	buffer := make([]byte, size)
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
@@ -0,0 +1,241 @@
+package uaa_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authorization code grant", func() {
+	var (
+		server        *httptest.Server
+		subject       uaa.Client
+		codeChallenge string
+		redirectURI   string
+		denyAccess    bool
+	)
+
+	BeforeEach(func() {
+		codeChallenge = ""
+		redirectURI = ""
+		denyAccess = false
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/oauth/authorize":
+				query := r.URL.Query()
+				Expect(query.Get("response_type")).To(Equal("code"))
+				Expect(query.Get("client_id")).To(Equal("my-cli"))
+				Expect(query.Get("code_challenge_method")).To(Equal("S256"))
+				codeChallenge = query.Get("code_challenge")
+				redirectURI = query.Get("redirect_uri")
+
+				callback := url.Values{"state": {query.Get("state")}, "code": {"my-code"}}
+				if denyAccess {
+					callback = url.Values{"state": {query.Get("state")}, "error": {"access_denied"}, "error_description": {"User denied access"}}
+				}
+				http.Redirect(w, r, redirectURI+"?"+callback.Encode(), http.StatusFound)
+			case "/oauth/token":
+				Expect(r.FormValue("grant_type")).To(Equal("authorization_code"))
+				Expect(r.FormValue("code")).To(Equal("my-code"))
+				Expect(r.FormValue("redirect_uri")).To(Equal(redirectURI))
+
+				challenge := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+				Expect(base64.RawURLEncoding.EncodeToString(challenge[:])).To(Equal(codeChallenge))
+
+				w.Write([]byte(`{"access_token":"sso-access-token","refresh_token":"sso-refresh-token","token_type":"bearer"}`))
+			}
+		}))
+		subject = uaa.NewClient(server.URL)
+		subject.ClientID = "my-cli"
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("AuthorizeURL", func() {
+		It("builds the authorize url with PKCE", func() {
+			pkce, err := uaa.NewPKCE()
+			Expect(err).ToNot(HaveOccurred())
+
+			authorizeURL, err := url.Parse(subject.AuthorizeURL(uaa.AuthorizationRequest{
+				RedirectURI: "http://127.0.0.1:8080/callback",
+				Scopes:      []string{"openid", "cloud_controller.read"},
+				State:       "some-state",
+				PKCE:        pkce,
+			}))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(authorizeURL.Path).To(Equal("/oauth/authorize"))
+			Expect(authorizeURL.Query()).To(Equal(url.Values{
+				"response_type":         {"code"},
+				"client_id":             {"my-cli"},
+				"redirect_uri":          {"http://127.0.0.1:8080/callback"},
+				"scope":                 {"openid cloud_controller.read"},
+				"state":                 {"some-state"},
+				"code_challenge":        {pkce.Challenge},
+				"code_challenge_method": {"S256"},
+			}))
+		})
+	})
+
+	Describe("AuthenticateWithAuthorizationCode", func() {
+		Context("with the loopback listener", func() {
+			var options uaa.AuthorizationCodeOptions
+
+			BeforeEach(func() {
+				options = uaa.AuthorizationCodeOptions{
+					Scopes: []string{"openid"},
+					OpenBrowser: func(authorizeURL string) error {
+						go func() {
+							resp, err := http.Get(authorizeURL)
+							if err == nil {
+								ioutil.ReadAll(resp.Body)
+								resp.Body.Close()
+							}
+						}()
+						return nil
+					},
+				}
+			})
+
+			It("receives the code and exchanges it for tokens", func() {
+				tokens, err := subject.AuthenticateWithAuthorizationCode(options)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(tokens.AccessToken).To(Equal("sso-access-token"))
+				Expect(tokens.RefreshToken).To(Equal("sso-refresh-token"))
+				Expect(redirectURI).To(MatchRegexp(`^http://127\.0\.0\.1:\d+/callback$`))
+			})
+
+			Context("when requests with a wrong or missing state reach the callback", func() {
+				var forgedStatuses []int
+
+				BeforeEach(func() {
+					openBrowser := options.OpenBrowser
+					options.OpenBrowser = func(authorizeURL string) error {
+						parsed, err := url.Parse(authorizeURL)
+						Expect(err).ToNot(HaveOccurred())
+
+						forgedStatuses = nil
+						for _, query := range []string{"?code=forged-code&state=wrong-state", "?error=access_denied", "?error=access_denied&state=wrong-state"} {
+							resp, err := http.Get(parsed.Query().Get("redirect_uri") + query)
+							Expect(err).ToNot(HaveOccurred())
+							resp.Body.Close()
+							forgedStatuses = append(forgedStatuses, resp.StatusCode)
+						}
+
+						return openBrowser(authorizeURL)
+					}
+				})
+
+				It("rejects them and keeps waiting for the real redirect", func() {
+					tokens, err := subject.AuthenticateWithAuthorizationCode(options)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(forgedStatuses).To(Equal([]int{http.StatusBadRequest, http.StatusBadRequest, http.StatusBadRequest}))
+					Expect(tokens.AccessToken).To(Equal("sso-access-token"))
+				})
+			})
+
+			Context("when the user denies access", func() {
+				BeforeEach(func() {
+					denyAccess = true
+				})
+
+				It("returns the authorization error", func() {
+					_, err := subject.AuthenticateWithAuthorizationCode(options)
+					Expect(err).To(MatchError("UAA Error: User denied access (access_denied)"))
+				})
+			})
+
+			Context("when the browser never comes back", func() {
+				BeforeEach(func() {
+					options.OpenBrowser = func(string) error { return nil }
+				})
+
+				It("gives up when the context is done", func() {
+					ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+					defer cancel()
+
+					_, err := subject.AuthenticateWithAuthorizationCodeWithContext(ctx, options)
+					Expect(err).To(Equal(context.DeadlineExceeded))
+				})
+			})
+
+			Context("when the browser can't be opened", func() {
+				BeforeEach(func() {
+					options.OpenBrowser = func(string) error { return errors.New("no browser") }
+				})
+
+				It("returns the error", func() {
+					_, err := subject.AuthenticateWithAuthorizationCode(options)
+					Expect(err).To(MatchError("no browser"))
+				})
+			})
+		})
+
+		Context("in headless mode", func() {
+			var pasted func(authorizeURL string) string
+
+			fetchCallback := func(authorizeURL string) *url.URL {
+				client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+					return http.ErrUseLastResponse
+				}}
+				resp, err := client.Get(authorizeURL)
+				Expect(err).ToNot(HaveOccurred())
+				resp.Body.Close()
+
+				location, err := url.Parse(resp.Header.Get("Location"))
+				Expect(err).ToNot(HaveOccurred())
+				return location
+			}
+
+			headless := func() (*uaa.Tokens, error) {
+				return subject.AuthenticateWithAuthorizationCode(uaa.AuthorizationCodeOptions{
+					RedirectURI: server.URL + "/login/callback",
+					PromptCode: func(authorizeURL string) (string, error) {
+						return pasted(authorizeURL), nil
+					},
+				})
+			}
+
+			It("accepts a pasted code", func() {
+				pasted = func(authorizeURL string) string {
+					return fetchCallback(authorizeURL).Query().Get("code") + "\n"
+				}
+
+				tokens, err := headless()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(tokens.AccessToken).To(Equal("sso-access-token"))
+			})
+
+			It("accepts the pasted redirect url and checks the state", func() {
+				pasted = func(authorizeURL string) string {
+					return fetchCallback(authorizeURL).String()
+				}
+
+				tokens, err := headless()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(tokens.AccessToken).To(Equal("sso-access-token"))
+
+				pasted = func(authorizeURL string) string {
+					return server.URL + "/login/callback?code=my-code&state=forged"
+				}
+
+				_, err = headless()
+				Expect(err).To(MatchError("Invalid authorization state"))
+			})
+		})
+	})
+})
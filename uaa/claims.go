@@ -0,0 +1,133 @@
+package uaa
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Claims struct {
+	JTI       string   `json:"jti"`
+	Sub       string   `json:"sub"`
+	UserID    string   `json:"user_id"`
+	UserName  string   `json:"user_name"`
+	Email     string   `json:"email"`
+	Origin    string   `json:"origin"`
+	ClientID  string   `json:"client_id"`
+	CID       string   `json:"cid"`
+	AZP       string   `json:"azp"`
+	Scope     []string `json:"scope"`
+	Audience  Audience `json:"aud"`
+	Issuer    string   `json:"iss"`
+	ZoneID    string   `json:"zid"`
+	Exp       int64    `json:"exp"`
+	Iat       int64    `json:"iat"`
+	Nbf       int64    `json:"nbf"`
+	GrantType string   `json:"grant_type"`
+	RevSig    string   `json:"rev_sig"`
+}
+
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+
+	*a = Audience(multiple)
+	return nil
+}
+
+func (a Audience) Contains(audience string) bool {
+	for _, aud := range a {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
+
+func ParseClaims(token string) (*Claims, error) {
+	segments, err := splitToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := decodeSegment(segments[1])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid token payload: %s", err.Error())
+	}
+
+	claims := new(Claims)
+	err = json.Unmarshal(payload, claims)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid token claims: %s", err.Error())
+	}
+
+	return claims, nil
+}
+
+// Subject returns the name to display for the token owner: the user name for
+// user tokens, or the client id for client credentials tokens.
+func (c *Claims) Subject() string {
+	if c.UserName != "" {
+		return c.UserName
+	}
+	if c.ClientID != "" {
+		return c.ClientID
+	}
+	return c.Sub
+}
+
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scope {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Claims) ExpiresAt() time.Time {
+	return time.Unix(c.Exp, 0)
+}
+
+func (c *Claims) IssuedAt() time.Time {
+	return time.Unix(c.Iat, 0)
+}
+
+func (c *Claims) Expired() bool {
+	return c.Exp != 0 && time.Now().After(c.ExpiresAt())
+}
+
+func stripTokenType(token string) string {
+	token = strings.TrimSpace(token)
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+	return token
+}
+
+func splitToken(token string) ([]string, error) {
+	token = stripTokenType(token)
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return nil, errors.New("Invalid token: expected 3 segments")
+	}
+
+	return segments, nil
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+}
@@ -0,0 +1,124 @@
+package uaa_test
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func encodeToken(payload string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"key-1","typ":"JWT"}`))
+	return header + "." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2lnbmF0dXJl"
+}
+
+var _ = Describe("Claims", func() {
+	Describe("ParseClaims", func() {
+		It("decodes the access token claims", func() {
+			token := encodeToken(`{
+				"jti": "a4d1a1e2",
+				"sub": "b5a6e1c3-2c1e-4f59-9fb5-1a0b7e2c3d4f",
+				"scope": ["cloud_controller.read", "openid"],
+				"client_id": "cf",
+				"cid": "cf",
+				"grant_type": "password",
+				"user_id": "b5a6e1c3-2c1e-4f59-9fb5-1a0b7e2c3d4f",
+				"origin": "uaa",
+				"user_name": "admin",
+				"email": "admin@example.com",
+				"iat": 1444000000,
+				"exp": 1444000600,
+				"iss": "https://uaa.example.com/oauth/token",
+				"zid": "uaa",
+				"aud": ["cf", "cloud_controller"]
+			}`)
+
+			claims, err := uaa.ParseClaims(token)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(claims.UserID).To(Equal("b5a6e1c3-2c1e-4f59-9fb5-1a0b7e2c3d4f"))
+			Expect(claims.UserName).To(Equal("admin"))
+			Expect(claims.Email).To(Equal("admin@example.com"))
+			Expect(claims.ClientID).To(Equal("cf"))
+			Expect(claims.Scope).To(Equal([]string{"cloud_controller.read", "openid"}))
+			Expect(claims.Audience).To(Equal(uaa.Audience{"cf", "cloud_controller"}))
+			Expect(claims.Issuer).To(Equal("https://uaa.example.com/oauth/token"))
+			Expect(claims.ZoneID).To(Equal("uaa"))
+			Expect(claims.GrantType).To(Equal("password"))
+			Expect(claims.ExpiresAt()).To(Equal(time.Unix(1444000600, 0)))
+			Expect(claims.IssuedAt()).To(Equal(time.Unix(1444000000, 0)))
+			Expect(claims.Expired()).To(BeTrue())
+		})
+
+		It("accepts a single string audience", func() {
+			claims, err := uaa.ParseClaims(encodeToken(`{"aud":"cf"}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claims.Audience).To(Equal(uaa.Audience{"cf"}))
+			Expect(claims.Audience.Contains("cf")).To(BeTrue())
+		})
+
+		It("accepts tokens prefixed with the token type", func() {
+			claims, err := uaa.ParseClaims("bearer " + encodeToken(`{"user_name":"admin"}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claims.UserName).To(Equal("admin"))
+		})
+
+		It("fails on malformed tokens", func() {
+			_, err := uaa.ParseClaims("not-a-jwt")
+			Expect(err).To(MatchError("Invalid token: expected 3 segments"))
+
+			_, err = uaa.ParseClaims("a.!!!.c")
+			Expect(err.Error()).To(ContainSubstring("Invalid token payload"))
+
+			_, err = uaa.ParseClaims(encodeToken(`{"exp": "soon"}`))
+			Expect(err.Error()).To(ContainSubstring("Invalid token claims"))
+		})
+	})
+
+	Describe("HasScope", func() {
+		It("checks the granted scopes", func() {
+			claims := uaa.Claims{Scope: []string{"cloud_controller.read"}}
+			Expect(claims.HasScope("cloud_controller.read")).To(BeTrue())
+			Expect(claims.HasScope("cloud_controller.admin")).To(BeFalse())
+		})
+	})
+
+	Describe("Subject", func() {
+		It("prefers the user name", func() {
+			claims := uaa.Claims{Sub: "some-guid", UserName: "admin", ClientID: "cf"}
+			Expect(claims.Subject()).To(Equal("admin"))
+		})
+
+		It("falls back to the client id for client tokens", func() {
+			claims := uaa.Claims{Sub: "my-client", ClientID: "my-client"}
+			Expect(claims.Subject()).To(Equal("my-client"))
+		})
+	})
+
+	Describe("Expired", func() {
+		It("is false for tokens in the future", func() {
+			claims := uaa.Claims{Exp: time.Now().Add(time.Hour).Unix()}
+			Expect(claims.Expired()).To(BeFalse())
+		})
+	})
+
+	Describe("Tokens", func() {
+		It("decodes the access and id tokens", func() {
+			tokens := uaa.Tokens{
+				AccessToken: encodeToken(`{"user_name":"admin","scope":["openid"]}`),
+				IDToken:     encodeToken(`{"user_name":"admin","email":"admin@example.com","aud":"cf"}`),
+			}
+
+			accessClaims, err := tokens.AccessTokenClaims()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(accessClaims.HasScope("openid")).To(BeTrue())
+
+			idClaims, err := tokens.IDTokenClaims()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(idClaims.Email).To(Equal("admin@example.com"))
+		})
+	})
+})
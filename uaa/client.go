@@ -1,93 +1,296 @@
 package uaa
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+
+	"github.com/tscolari/cfapi/metrics"
+	"github.com/tscolari/cfapi/telemetry"
+	"github.com/tscolari/cfapi/trace"
 )
 
 type Refresher interface {
 	RefreshToken(refreshToken string) (*Tokens, error)
 }
 
+type ContextRefresher interface {
+	RefreshTokenWithContext(ctx context.Context, refreshToken string) (*Tokens, error)
+}
+
 type Client struct {
-	endpoint string
+	endpoint          string
+	httpClient        *http.Client
+	tokenInfoCache    *tokenInfoCache
+	TracePrinter      trace.Printer
+	Metrics           metrics.Collector
+	Tracer            telemetry.Tracer
+	ClientID          string
+	ClientSecret      string
+	ClientAuthMethod  ClientAuthMethod
+	TokenInfoCacheTTL time.Duration
+	ZoneID            string
+	ZoneSubdomain     string
+}
+
+type ClientAuthMethod string
+
+const (
+	ClientAuthBasic ClientAuthMethod = "client_secret_basic"
+	ClientAuthPost  ClientAuthMethod = "client_secret_post"
+	ClientAuthNone  ClientAuthMethod = "none"
+)
+
+var defaultHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+// tokenKeysHTTPClient always verifies certificates: whoever serves the token
+// keys decides which tokens are trusted.
+var tokenKeysHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSClientConfig:     &tls.Config{MinVersion: tls.VersionTLS12},
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 2,
+		IdleConnTimeout:     90 * time.Second,
+	},
 }
 
 func NewClient(endpoint string) Client {
+	return NewClientWithHTTPClient(endpoint, defaultHTTPClient)
+}
+
+func NewClientWithHTTPClient(endpoint string, httpClient *http.Client) Client {
 	client := Client{
-		endpoint: endpoint,
+		endpoint:          endpoint,
+		httpClient:        httpClient,
+		ClientID:          "cf",
+		TokenInfoCacheTTL: 10 * time.Second,
+		tokenInfoCache:    newTokenInfoCache(),
 	}
 	return client
 }
 
 func (c *Client) Authenticate(username, password string) (*Tokens, error) {
+	return c.AuthenticateWithContext(context.Background(), username, password)
+}
+
+func (c *Client) AuthenticateWithContext(ctx context.Context, username, password string) (*Tokens, error) {
 	data := url.Values{}
 	data.Set("grant_type", "password")
 	data.Set("scope", "")
 	data.Set("username", username)
 	data.Set("password", password)
 
-	return c.fetchToken(data)
+	return c.fetchToken(ctx, data)
+}
+
+func (c *Client) AuthenticateWithPasscode(passcode string) (*Tokens, error) {
+	return c.AuthenticateWithPasscodeWithContext(context.Background(), passcode)
+}
+
+func (c *Client) AuthenticateWithPasscodeWithContext(ctx context.Context, passcode string) (*Tokens, error) {
+	data := url.Values{
+		"grant_type": {"password"},
+		"scope":      {""},
+		"passcode":   {passcode},
+	}
+
+	return c.fetchToken(ctx, data)
 }
 
 func (c *Client) RefreshToken(refreshToken string) (*Tokens, error) {
+	return c.RefreshTokenWithContext(context.Background(), refreshToken)
+}
+
+func (c *Client) RefreshTokenWithContext(ctx context.Context, refreshToken string) (*Tokens, error) {
 	data := url.Values{
 		"refresh_token": {refreshToken},
 		"grant_type":    {"refresh_token"},
 		"scope":         {""},
 	}
 
-	return c.fetchToken(data)
+	return c.fetchToken(ctx, data)
 }
 
-func (c *Client) fetchToken(data url.Values) (*Tokens, error) {
-	path := fmt.Sprintf("%s/oauth/token", c.endpoint)
-	request, err := http.NewRequest("POST", path, strings.NewReader(data.Encode()))
-	if err != nil {
-		return nil, err
+func (c *Client) AuthenticateWithClientCredentials(scopes ...string) (*Tokens, error) {
+	return c.AuthenticateWithClientCredentialsWithContext(context.Background(), scopes...)
+}
+
+func (c *Client) AuthenticateWithClientCredentialsWithContext(ctx context.Context, scopes ...string) (*Tokens, error) {
+	data := url.Values{
+		"grant_type": {"client_credentials"},
+	}
+	if len(scopes) > 0 {
+		data.Set("scope", strings.Join(scopes, " "))
 	}
 
-	respBytes, err := c.runRequest(request)
+	return c.fetchToken(ctx, data)
+}
+
+func (c *Client) fetchToken(ctx context.Context, data url.Values) (tokens *Tokens, err error) {
+	ctx, span := telemetry.StartSpan(c.Tracer, ctx, "uaa POST /oauth/token")
+	defer func() { telemetry.EndSpan(span, err) }()
+	span.SetAttribute("uaa.grant_type", data.Get("grant_type"))
+
+	request, err := c.newClientAuthRequest(ctx, "/oauth/token", data)
 	if err != nil {
 		return nil, err
 	}
 
 	uaaResp := new(authenticationResponse)
-	err = json.Unmarshal(respBytes, &uaaResp)
+	err = c.runJSONRequest(request, uaaResp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse response (%s)", err.Error())
+		return nil, err
 	}
 
 	if uaaResp.ErrorCode != "" {
-		return nil, fmt.Errorf("UAA Error: %s (%s)", uaaResp.ErrorDescription, uaaResp.ErrorCode)
+		return nil, &Error{ErrorCode: uaaResp.ErrorCode, Description: uaaResp.ErrorDescription}
 	}
 
-	return &Tokens{
+	tokens = &Tokens{
 		AccessToken:  uaaResp.AccessToken,
 		RefreshToken: uaaResp.RefreshToken,
 		TokenType:    uaaResp.TokenType,
-	}, nil
+		IDToken:      uaaResp.IDToken,
+	}
+	if uaaResp.ExpiresIn > 0 {
+		tokens.Expiry = time.Now().Add(time.Duration(uaaResp.ExpiresIn) * time.Second)
+	}
+
+	return tokens, nil
 }
 
-func (c *Client) runRequest(request *http.Request) ([]byte, error) {
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequest(method, c.endpoint+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	request = request.WithContext(ctx)
+	request.Header.Set("Accept", "application/json")
+	c.setZoneHeaders(ctx, request)
+	telemetry.Inject(c.Tracer, ctx, request.Header)
+	return request, nil
+}
+
+func (c *Client) newFormRequest(ctx context.Context, method, path string, data url.Values) (*http.Request, error) {
+	request, err := c.newRequest(ctx, method, path, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
 	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	request.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("cf:")))
+	return request, nil
+}
+
+func (c *Client) newJSONRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid request body: %s", err.Error())
+	}
+
+	request, err := c.newRequest(ctx, method, path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	return request, nil
+}
+
+func (c *Client) runJSONRequest(request *http.Request, response interface{}) error {
+	respBytes, err := c.runRequest(request)
+	if err != nil {
+		return err
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return fmt.Errorf("Failed to parse response (%s)", err.Error())
+	}
+
+	return nil
+}
+
+func (c *Client) runRequest(request *http.Request) ([]byte, error) {
+	trace.DumpRequest(c.TracePrinter, request)
+	start := time.Now()
+	resp, err := c.httpClient.Do(request)
+	if err != nil {
+		c.observeRequest(request, 0, start)
+		return nil, err
+	}
+	defer drainAndClose(resp.Body)
+
+	c.observeRequest(request, resp.StatusCode, start)
+	trace.DumpResponse(c.TracePrinter, resp)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
 
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newError(resp.StatusCode, body)
 	}
 
-	client := &http.Client{Transport: tr}
-	resp, err := client.Do(request)
+	return body, nil
+}
+
+func (c *Client) observeRequest(request *http.Request, statusCode int, start time.Time) {
+	if c.Metrics == nil {
+		return
+	}
+
+	c.Metrics.ObserveRequest(request.Method, metrics.PathTemplate(request.URL.Path), statusCode, time.Since(start))
+}
+
+func (c *Client) newClientAuthRequest(ctx context.Context, path string, data url.Values) (*http.Request, error) {
+	switch c.ClientAuthMethod {
+	case ClientAuthPost:
+		data.Set("client_id", c.ClientID)
+		data.Set("client_secret", c.ClientSecret)
+	case ClientAuthNone:
+		data.Set("client_id", c.ClientID)
+	}
+
+	request, err := c.newFormRequest(ctx, "POST", path, data)
 	if err != nil {
 		return nil, err
 	}
 
-	return ioutil.ReadAll(resp.Body)
+	if c.ClientAuthMethod == "" || c.ClientAuthMethod == ClientAuthBasic {
+		request.SetBasicAuth(c.ClientID, c.ClientSecret)
+	}
+
+	return request, nil
+}
+
+func setBearerAuth(request *http.Request, accessToken string) {
+	request.Header.Set("Authorization", "bearer "+stripTokenType(accessToken))
+}
+
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, io.LimitReader(body, 4096))
+	body.Close()
 }
@@ -1,12 +1,21 @@
 package uaa_test
 
 import (
+	"bytes"
+	"context"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync/atomic"
 
 	"code.google.com/p/go-uuid/uuid"
+	"github.com/tscolari/cfapi/metrics"
+	"github.com/tscolari/cfapi/telemetry"
+	telemetryfakes "github.com/tscolari/cfapi/telemetry/fakes"
+	"github.com/tscolari/cfapi/trace"
 	"github.com/tscolari/cfapi/uaa"
 
 	. "github.com/onsi/ginkgo"
@@ -61,6 +70,73 @@ var _ = Describe("Client", func() {
 				Expect(tokens.RefreshToken).To(Equal("5678"))
 				Expect(tokens.TokenType).To(Equal("bearer"))
 			})
+
+			Context("when a TracePrinter is set", func() {
+				It("dumps the request and response without credentials or tokens", func() {
+					output := new(bytes.Buffer)
+					subject.TracePrinter = trace.NewWriterPrinter(output)
+
+					_, err := subject.Authenticate(username, password)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(output.String()).To(ContainSubstring("POST /oauth/token HTTP/1.1"))
+					Expect(output.String()).To(ContainSubstring("Authorization: Basic [PRIVATE DATA HIDDEN]"))
+					Expect(output.String()).To(ContainSubstring("password=[PRIVATE DATA HIDDEN]"))
+					Expect(output.String()).To(ContainSubstring(`"access_token":"[PRIVATE DATA HIDDEN]"`))
+					Expect(output.String()).ToNot(ContainSubstring(password))
+					Expect(output.String()).ToNot(ContainSubstring("5678"))
+				})
+			})
+
+			Context("when Metrics is set", func() {
+				It("records the token request", func() {
+					collector := metrics.NewPrometheusCollector("")
+					subject.Metrics = collector
+
+					_, err := subject.Authenticate(username, password)
+					Expect(err).ToNot(HaveOccurred())
+
+					output := new(bytes.Buffer)
+					collector.WriteTo(output)
+					Expect(output.String()).To(ContainSubstring(`cfapi_requests_total{method="POST",path="/oauth/token",code="200"} 1`))
+				})
+			})
+
+			Context("when a Tracer is set", func() {
+				var traceParent string
+
+				BeforeEach(func() {
+					tokenHandler := httpHandler
+					httpHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						traceParent = r.Header.Get("traceparent")
+						tokenHandler.ServeHTTP(w, r)
+					})
+				})
+
+				It("wraps the token request in a span and propagates the trace context", func() {
+					span := new(telemetryfakes.FakeSpan)
+					tracer := new(telemetryfakes.FakeTracer)
+					tracer.StartStub = func(ctx context.Context, name string) (context.Context, telemetry.Span) {
+						return ctx, span
+					}
+					tracer.InjectStub = func(ctx context.Context, header http.Header) {
+						header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+					}
+					subject.Tracer = tracer
+
+					_, err := subject.AuthenticateWithContext(context.Background(), username, password)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, name := tracer.StartArgsForCall(0)
+					Expect(name).To(Equal("uaa POST /oauth/token"))
+					key, value := span.SetAttributeArgsForCall(0)
+					Expect(key).To(Equal("uaa.grant_type"))
+					Expect(value).To(Equal("password"))
+					Expect(span.EndCallCount()).To(Equal(1))
+					Expect(tracer.InjectCallCount()).To(Equal(1))
+					Expect(traceParent).To(Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+				})
+			})
 		})
 
 		Context("when the UAA returns an error", func() {
@@ -77,6 +153,45 @@ var _ = Describe("Client", func() {
 			})
 		})
 
+		Context("when the UAA returns an HTML error page", func() {
+			BeforeEach(func() {
+				httpHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/html")
+					w.WriteHeader(http.StatusBadGateway)
+					w.Write([]byte("<html><body><h1>502 Bad Gateway</h1></body></html>"))
+				})
+			})
+
+			It("returns an error with the status", func() {
+				_, err := subject.Authenticate("3", "4")
+				Expect(err).To(MatchError("UAA Error: Bad Gateway (502)"))
+
+				uaaErr, ok := err.(*uaa.Error)
+				Expect(ok).To(BeTrue())
+				Expect(uaaErr.StatusCode).To(Equal(http.StatusBadGateway))
+			})
+		})
+
+		Context("when the UAA rejects the credentials", func() {
+			BeforeEach(func() {
+				httpHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(`{"error":"unauthorized","error_description":"Bad credentials"}`))
+				})
+			})
+
+			It("returns a typed error", func() {
+				_, err := subject.Authenticate("3", "4")
+				Expect(err).To(MatchError("UAA Error: Bad credentials (unauthorized)"))
+
+				uaaErr, ok := err.(*uaa.Error)
+				Expect(ok).To(BeTrue())
+				Expect(uaaErr.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(uaaErr.ErrorCode).To(Equal("unauthorized"))
+			})
+		})
+
 		Context("when there's an error parsing the response", func() {
 			BeforeEach(func() {
 				httpHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -116,4 +231,43 @@ var _ = Describe("Client", func() {
 			})
 		})
 	})
+
+	Describe("connection reuse", func() {
+		var (
+			newConnections int32
+			requests       int32
+		)
+
+		JustBeforeEach(func() {
+			server.Close()
+
+			atomic.StoreInt32(&newConnections, 0)
+			atomic.StoreInt32(&requests, 0)
+			server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1)%2 == 0 {
+					w.Header().Set("Content-Type", "text/html")
+					w.WriteHeader(http.StatusServiceUnavailable)
+					w.Write([]byte("<html><body>" + strings.Repeat("unavailable ", 100) + "</body></html>"))
+					return
+				}
+				w.Write([]byte(`{"access_token":"1234","refresh_token":"5678","token_type":"bearer"}`))
+			}))
+			server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					atomic.AddInt32(&newConnections, 1)
+				}
+			}
+			server.Start()
+			subject = uaa.NewClient(server.URL)
+		})
+
+		It("reuses connections for successful and failed requests", func() {
+			for i := 0; i < 100; i++ {
+				subject.RefreshToken("some-token")
+			}
+
+			Expect(atomic.LoadInt32(&requests)).To(BeEquivalentTo(100))
+			Expect(atomic.LoadInt32(&newConnections)).To(BeEquivalentTo(1))
+		})
+	})
 })
@@ -0,0 +1,76 @@
+package uaa
+
+import (
+	"context"
+	"fmt"
+)
+
+type Credentials struct {
+	GrantType string
+	Username  string
+	Password  string
+	Scopes    []string
+}
+
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (*Credentials, error)
+}
+
+type CredentialsProviderFunc func(ctx context.Context) (*Credentials, error)
+
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (*Credentials, error) {
+	return f(ctx)
+}
+
+type CredentialsAuthenticator interface {
+	AuthenticateWithCredentialsWithContext(ctx context.Context, credentials Credentials) (*Tokens, error)
+}
+
+func StaticCredentials(username, password string) CredentialsProvider {
+	return CredentialsProviderFunc(func(context.Context) (*Credentials, error) {
+		return &Credentials{GrantType: "password", Username: username, Password: password}, nil
+	})
+}
+
+func ClientCredentials(scopes ...string) CredentialsProvider {
+	return CredentialsProviderFunc(func(context.Context) (*Credentials, error) {
+		return &Credentials{GrantType: "client_credentials", Scopes: scopes}, nil
+	})
+}
+
+func PromptCredentials(prompt func(ctx context.Context) (username, password string, err error)) CredentialsProvider {
+	return CredentialsProviderFunc(func(ctx context.Context) (*Credentials, error) {
+		username, password, err := prompt(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Credentials{GrantType: "password", Username: username, Password: password}, nil
+	})
+}
+
+func (c *Client) AuthenticateWithCredentials(credentials Credentials) (*Tokens, error) {
+	return c.AuthenticateWithCredentialsWithContext(context.Background(), credentials)
+}
+
+func (c *Client) AuthenticateWithCredentialsWithContext(ctx context.Context, credentials Credentials) (*Tokens, error) {
+	switch credentials.GrantType {
+	case "password":
+		return c.AuthenticateWithContext(ctx, credentials.Username, credentials.Password)
+	case "client_credentials":
+		return c.AuthenticateWithClientCredentialsWithContext(ctx, credentials.Scopes...)
+	}
+
+	return nil, fmt.Errorf("Unsupported grant type: %s", credentials.GrantType)
+}
+
+// IsInvalidGrant reports whether UAA rejected the grant itself, e.g. an
+// expired or revoked refresh token, rather than failing to process it.
+func IsInvalidGrant(err error) bool {
+	uaaErr, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+
+	return uaaErr.ErrorCode == "invalid_grant" || uaaErr.ErrorCode == "invalid_token"
+}
@@ -0,0 +1,92 @@
+package uaa_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Credentials", func() {
+	var (
+		server  *httptest.Server
+		subject uaa.Client
+		form    url.Values
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).To(Succeed())
+			form = r.PostForm
+			w.Write([]byte(`{"access_token":"new-access-token","token_type":"bearer"}`))
+		}))
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	authenticate := func(provider uaa.CredentialsProvider) (*uaa.Tokens, error) {
+		credentials, err := provider.Credentials(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return subject.AuthenticateWithCredentials(*credentials)
+	}
+
+	It("authenticates with static credentials", func() {
+		tokens, err := authenticate(uaa.StaticCredentials("admin", "admin-password"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens.AccessToken).To(Equal("new-access-token"))
+
+		Expect(form.Get("grant_type")).To(Equal("password"))
+		Expect(form.Get("username")).To(Equal("admin"))
+		Expect(form.Get("password")).To(Equal("admin-password"))
+	})
+
+	It("authenticates with client credentials", func() {
+		_, err := authenticate(uaa.ClientCredentials("cloud_controller.admin"))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(form.Get("grant_type")).To(Equal("client_credentials"))
+		Expect(form.Get("scope")).To(Equal("cloud_controller.admin"))
+	})
+
+	It("authenticates with prompted credentials", func() {
+		_, err := authenticate(uaa.PromptCredentials(func(context.Context) (string, string, error) {
+			return "marissa", "koala", nil
+		}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(form.Get("username")).To(Equal("marissa"))
+		Expect(form.Get("password")).To(Equal("koala"))
+	})
+
+	It("returns prompt errors", func() {
+		_, err := authenticate(uaa.PromptCredentials(func(context.Context) (string, string, error) {
+			return "", "", errors.New("no terminal")
+		}))
+		Expect(err).To(MatchError("no terminal"))
+	})
+
+	It("rejects unknown grant types", func() {
+		_, err := subject.AuthenticateWithCredentials(uaa.Credentials{GrantType: "implicit"})
+		Expect(err).To(MatchError("Unsupported grant type: implicit"))
+	})
+
+	Describe("IsInvalidGrant", func() {
+		It("detects rejected grants", func() {
+			Expect(uaa.IsInvalidGrant(&uaa.Error{ErrorCode: "invalid_grant"})).To(BeTrue())
+			Expect(uaa.IsInvalidGrant(&uaa.Error{ErrorCode: "invalid_token"})).To(BeTrue())
+			Expect(uaa.IsInvalidGrant(&uaa.Error{ErrorCode: "unauthorized"})).To(BeFalse())
+			Expect(uaa.IsInvalidGrant(errors.New("invalid_grant"))).To(BeFalse())
+			Expect(uaa.IsInvalidGrant(nil)).To(BeFalse())
+		})
+	})
+})
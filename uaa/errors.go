@@ -0,0 +1,55 @@
+package uaa
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type Error struct {
+	StatusCode  int    `json:"-"`
+	ErrorCode   string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	if e.ErrorCode == "" {
+		return fmt.Sprintf("UAA Error: %s (%d)", e.Description, e.StatusCode)
+	}
+
+	return fmt.Sprintf("UAA Error: %s (%s)", e.Description, e.ErrorCode)
+}
+
+func newError(statusCode int, body []byte) *Error {
+	uaaErr := &Error{StatusCode: statusCode}
+
+	err := json.Unmarshal(body, uaaErr)
+	if err != nil || (uaaErr.ErrorCode == "" && uaaErr.Description == "") {
+		uaaErr.ErrorCode = ""
+		uaaErr.Description = http.StatusText(statusCode)
+	}
+
+	return uaaErr
+}
+
+// IsConflict reports whether err is a conflict, including a stale version
+// (see IsVersionMismatch).
+func IsConflict(err error) bool {
+	uaaErr, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+
+	return uaaErr.StatusCode == http.StatusConflict || uaaErr.StatusCode == http.StatusPreconditionFailed
+}
+
+// IsVersionMismatch reports whether err was caused by a stale If-Match
+// version, as opposed to a conflict with an existing resource.
+func IsVersionMismatch(err error) bool {
+	uaaErr, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+
+	return uaaErr.StatusCode == http.StatusPreconditionFailed
+}
@@ -0,0 +1,119 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tscolari/cfapi/uaa"
+)
+
+type FakeCredentialsAuthenticator struct {
+	AuthenticateWithCredentialsWithContextStub        func(context.Context, uaa.Credentials) (*uaa.Tokens, error)
+	authenticateWithCredentialsWithContextMutex       sync.RWMutex
+	authenticateWithCredentialsWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 uaa.Credentials
+	}
+	authenticateWithCredentialsWithContextReturns struct {
+		result1 *uaa.Tokens
+		result2 error
+	}
+	authenticateWithCredentialsWithContextReturnsOnCall map[int]struct {
+		result1 *uaa.Tokens
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCredentialsAuthenticator) AuthenticateWithCredentialsWithContext(arg1 context.Context, arg2 uaa.Credentials) (*uaa.Tokens, error) {
+	fake.authenticateWithCredentialsWithContextMutex.Lock()
+	ret, specificReturn := fake.authenticateWithCredentialsWithContextReturnsOnCall[len(fake.authenticateWithCredentialsWithContextArgsForCall)]
+	fake.authenticateWithCredentialsWithContextArgsForCall = append(fake.authenticateWithCredentialsWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 uaa.Credentials
+	}{arg1, arg2})
+	stub := fake.AuthenticateWithCredentialsWithContextStub
+	fakeReturns := fake.authenticateWithCredentialsWithContextReturns
+	fake.recordInvocation("AuthenticateWithCredentialsWithContext", []interface{}{arg1, arg2})
+	fake.authenticateWithCredentialsWithContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredentialsAuthenticator) AuthenticateWithCredentialsWithContextCallCount() int {
+	fake.authenticateWithCredentialsWithContextMutex.RLock()
+	defer fake.authenticateWithCredentialsWithContextMutex.RUnlock()
+	return len(fake.authenticateWithCredentialsWithContextArgsForCall)
+}
+
+func (fake *FakeCredentialsAuthenticator) AuthenticateWithCredentialsWithContextCalls(stub func(context.Context, uaa.Credentials) (*uaa.Tokens, error)) {
+	fake.authenticateWithCredentialsWithContextMutex.Lock()
+	defer fake.authenticateWithCredentialsWithContextMutex.Unlock()
+	fake.AuthenticateWithCredentialsWithContextStub = stub
+}
+
+func (fake *FakeCredentialsAuthenticator) AuthenticateWithCredentialsWithContextArgsForCall(i int) (context.Context, uaa.Credentials) {
+	fake.authenticateWithCredentialsWithContextMutex.RLock()
+	defer fake.authenticateWithCredentialsWithContextMutex.RUnlock()
+	argsForCall := fake.authenticateWithCredentialsWithContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredentialsAuthenticator) AuthenticateWithCredentialsWithContextReturns(result1 *uaa.Tokens, result2 error) {
+	fake.authenticateWithCredentialsWithContextMutex.Lock()
+	defer fake.authenticateWithCredentialsWithContextMutex.Unlock()
+	fake.AuthenticateWithCredentialsWithContextStub = nil
+	fake.authenticateWithCredentialsWithContextReturns = struct {
+		result1 *uaa.Tokens
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredentialsAuthenticator) AuthenticateWithCredentialsWithContextReturnsOnCall(i int, result1 *uaa.Tokens, result2 error) {
+	fake.authenticateWithCredentialsWithContextMutex.Lock()
+	defer fake.authenticateWithCredentialsWithContextMutex.Unlock()
+	fake.AuthenticateWithCredentialsWithContextStub = nil
+	if fake.authenticateWithCredentialsWithContextReturnsOnCall == nil {
+		fake.authenticateWithCredentialsWithContextReturnsOnCall = make(map[int]struct {
+			result1 *uaa.Tokens
+			result2 error
+		})
+	}
+	fake.authenticateWithCredentialsWithContextReturnsOnCall[i] = struct {
+		result1 *uaa.Tokens
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredentialsAuthenticator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.authenticateWithCredentialsWithContextMutex.RLock()
+	defer fake.authenticateWithCredentialsWithContextMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeCredentialsAuthenticator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ uaa.CredentialsAuthenticator = new(FakeCredentialsAuthenticator)
@@ -0,0 +1,113 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/tscolari/cfapi/uaa"
+)
+
+type FakeRevoker struct {
+	RevokeTokenStub        func(string, string) error
+	revokeTokenMutex       sync.RWMutex
+	revokeTokenArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	revokeTokenReturns struct {
+		result1 error
+	}
+	revokeTokenReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeRevoker) RevokeToken(arg1 string, arg2 string) error {
+	fake.revokeTokenMutex.Lock()
+	ret, specificReturn := fake.revokeTokenReturnsOnCall[len(fake.revokeTokenArgsForCall)]
+	fake.revokeTokenArgsForCall = append(fake.revokeTokenArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RevokeTokenStub
+	fakeReturns := fake.revokeTokenReturns
+	fake.recordInvocation("RevokeToken", []interface{}{arg1, arg2})
+	fake.revokeTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRevoker) RevokeTokenCallCount() int {
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	return len(fake.revokeTokenArgsForCall)
+}
+
+func (fake *FakeRevoker) RevokeTokenCalls(stub func(string, string) error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = stub
+}
+
+func (fake *FakeRevoker) RevokeTokenArgsForCall(i int) (string, string) {
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	argsForCall := fake.revokeTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRevoker) RevokeTokenReturns(result1 error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = nil
+	fake.revokeTokenReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRevoker) RevokeTokenReturnsOnCall(i int, result1 error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = nil
+	if fake.revokeTokenReturnsOnCall == nil {
+		fake.revokeTokenReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeTokenReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRevoker) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeRevoker) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ uaa.Revoker = new(FakeRevoker)
@@ -0,0 +1,254 @@
+package uaa
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+const (
+	GroupMemberUser  = "USER"
+	GroupMemberGroup = "GROUP"
+)
+
+type Group struct {
+	ID          string        `json:"id,omitempty"`
+	DisplayName string        `json:"displayName,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Members     []GroupMember `json:"members,omitempty"`
+	ZoneID      string        `json:"zoneId,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+	Schemas     []string      `json:"schemas,omitempty"`
+}
+
+type GroupMember struct {
+	Value  string `json:"value"`
+	Type   string `json:"type,omitempty"`
+	Origin string `json:"origin,omitempty"`
+}
+
+type GroupsPage struct {
+	Resources    []Group `json:"resources"`
+	StartIndex   int     `json:"startIndex"`
+	ItemsPerPage int     `json:"itemsPerPage"`
+	TotalResults int     `json:"totalResults"`
+}
+
+type GroupMapping struct {
+	GroupID       string `json:"groupId"`
+	DisplayName   string `json:"displayName,omitempty"`
+	ExternalGroup string `json:"externalGroup"`
+	Origin        string `json:"origin"`
+	Meta          *Meta  `json:"meta,omitempty"`
+}
+
+type GroupMappingsPage struct {
+	Resources    []GroupMapping `json:"resources"`
+	StartIndex   int            `json:"startIndex"`
+	ItemsPerPage int            `json:"itemsPerPage"`
+	TotalResults int            `json:"totalResults"`
+}
+
+func (c *Client) CreateGroup(accessToken string, group Group) (*Group, error) {
+	return c.CreateGroupWithContext(context.Background(), accessToken, group)
+}
+
+func (c *Client) CreateGroupWithContext(ctx context.Context, accessToken string, group Group) (*Group, error) {
+	return c.sendGroup(ctx, "POST", "/Groups", accessToken, group, "")
+}
+
+func (c *Client) GetGroup(accessToken, groupID string) (*Group, error) {
+	return c.GetGroupWithContext(context.Background(), accessToken, groupID)
+}
+
+func (c *Client) GetGroupWithContext(ctx context.Context, accessToken, groupID string) (*Group, error) {
+	if groupID == "" {
+		return nil, errors.New("Missing group id")
+	}
+
+	group := new(Group)
+	err := c.scimRequest(ctx, "GET", "/Groups/"+url.PathEscape(groupID), accessToken, group)
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (c *Client) FindGroups(accessToken string, query ScimQuery) (*GroupsPage, error) {
+	return c.FindGroupsWithContext(context.Background(), accessToken, query)
+}
+
+func (c *Client) FindGroupsWithContext(ctx context.Context, accessToken string, query ScimQuery) (*GroupsPage, error) {
+	page := new(GroupsPage)
+	err := c.scimRequest(ctx, "GET", "/Groups"+query.encode(), accessToken, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (c *Client) FindAllGroups(accessToken string, query ScimQuery) ([]Group, error) {
+	return c.FindAllGroupsWithContext(context.Background(), accessToken, query)
+}
+
+func (c *Client) FindAllGroupsWithContext(ctx context.Context, accessToken string, query ScimQuery) ([]Group, error) {
+	var groups []Group
+	err := findAll(query, func(query ScimQuery) (int, int, error) {
+		page, err := c.FindGroupsWithContext(ctx, accessToken, query)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		groups = append(groups, page.Resources...)
+		return len(page.Resources), page.TotalResults, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (c *Client) UpdateGroup(accessToken string, group Group) (*Group, error) {
+	return c.UpdateGroupWithContext(context.Background(), accessToken, group)
+}
+
+func (c *Client) UpdateGroupWithContext(ctx context.Context, accessToken string, group Group) (*Group, error) {
+	if group.ID == "" {
+		return nil, errors.New("Missing group id")
+	}
+
+	if group.Meta == nil {
+		return nil, errors.New("Missing group version")
+	}
+
+	return c.sendGroup(ctx, "PUT", "/Groups/"+url.PathEscape(group.ID), accessToken, group, ifMatch(group.Meta))
+}
+
+func (c *Client) DeleteGroup(accessToken, groupID string) error {
+	return c.DeleteGroupWithContext(context.Background(), accessToken, groupID)
+}
+
+func (c *Client) DeleteGroupWithContext(ctx context.Context, accessToken, groupID string) error {
+	if groupID == "" {
+		return errors.New("Missing group id")
+	}
+
+	return c.scimRequest(ctx, "DELETE", "/Groups/"+url.PathEscape(groupID), accessToken, nil)
+}
+
+func (c *Client) AddGroupMember(accessToken, groupID string, member GroupMember) (*GroupMember, error) {
+	return c.AddGroupMemberWithContext(context.Background(), accessToken, groupID, member)
+}
+
+func (c *Client) AddGroupMemberWithContext(ctx context.Context, accessToken, groupID string, member GroupMember) (*GroupMember, error) {
+	if groupID == "" {
+		return nil, errors.New("Missing group id")
+	}
+	if member.Type == "" {
+		member.Type = GroupMemberUser
+	}
+
+	result := new(GroupMember)
+	err := c.sendScimJSON(ctx, "POST", "/Groups/"+url.PathEscape(groupID)+"/members", accessToken, member, "", result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) RemoveGroupMember(accessToken, groupID, memberID string) error {
+	return c.RemoveGroupMemberWithContext(context.Background(), accessToken, groupID, memberID)
+}
+
+func (c *Client) RemoveGroupMemberWithContext(ctx context.Context, accessToken, groupID, memberID string) error {
+	if groupID == "" {
+		return errors.New("Missing group id")
+	}
+	if memberID == "" {
+		return errors.New("Missing member id")
+	}
+
+	return c.scimRequest(ctx, "DELETE", "/Groups/"+url.PathEscape(groupID)+"/members/"+url.PathEscape(memberID), accessToken, nil)
+}
+
+func (c *Client) GroupMembers(accessToken, groupID string) ([]GroupMember, error) {
+	return c.GroupMembersWithContext(context.Background(), accessToken, groupID)
+}
+
+func (c *Client) GroupMembersWithContext(ctx context.Context, accessToken, groupID string) ([]GroupMember, error) {
+	if groupID == "" {
+		return nil, errors.New("Missing group id")
+	}
+
+	var members []GroupMember
+	err := c.scimRequest(ctx, "GET", "/Groups/"+url.PathEscape(groupID)+"/members", accessToken, &members)
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (c *Client) MapExternalGroup(accessToken string, mapping GroupMapping) (*GroupMapping, error) {
+	return c.MapExternalGroupWithContext(context.Background(), accessToken, mapping)
+}
+
+func (c *Client) MapExternalGroupWithContext(ctx context.Context, accessToken string, mapping GroupMapping) (*GroupMapping, error) {
+	result := new(GroupMapping)
+	err := c.sendScimJSON(ctx, "POST", "/Groups/External", accessToken, mapping, "", result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) UnmapExternalGroup(accessToken string, mapping GroupMapping) error {
+	return c.UnmapExternalGroupWithContext(context.Background(), accessToken, mapping)
+}
+
+func (c *Client) UnmapExternalGroupWithContext(ctx context.Context, accessToken string, mapping GroupMapping) error {
+	if mapping.GroupID == "" {
+		return errors.New("Missing group id")
+	}
+	if mapping.ExternalGroup == "" {
+		return errors.New("Missing external group")
+	}
+	if mapping.Origin == "" {
+		return errors.New("Missing origin")
+	}
+
+	path := "/Groups/External/groupId/" + url.PathEscape(mapping.GroupID) +
+		"/externalGroup/" + url.PathEscape(mapping.ExternalGroup) +
+		"/origin/" + url.PathEscape(mapping.Origin)
+
+	return c.scimRequest(ctx, "DELETE", path, accessToken, nil)
+}
+
+func (c *Client) ExternalGroupMappings(accessToken string, query ScimQuery) (*GroupMappingsPage, error) {
+	return c.ExternalGroupMappingsWithContext(context.Background(), accessToken, query)
+}
+
+func (c *Client) ExternalGroupMappingsWithContext(ctx context.Context, accessToken string, query ScimQuery) (*GroupMappingsPage, error) {
+	page := new(GroupMappingsPage)
+	err := c.scimRequest(ctx, "GET", "/Groups/External"+query.encode(), accessToken, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (c *Client) sendGroup(ctx context.Context, method, path, accessToken string, group Group, version string) (*Group, error) {
+	result := new(Group)
+	err := c.sendScimJSON(ctx, method, path, accessToken, group, version, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
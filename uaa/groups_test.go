@@ -0,0 +1,295 @@
+package uaa_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Groups", func() {
+	var (
+		server   *httptest.Server
+		handler  http.HandlerFunc
+		subject  uaa.Client
+		requests []*http.Request
+		bodies   []string
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = nil
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		}
+	})
+
+	JustBeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("bearer admin-token"))
+			body, err := ioutil.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+
+			handler(w, r)
+		}))
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("CreateGroup", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":"group-guid","displayName":"cloud_controller.admin","meta":{"version":0}}`))
+			}
+		})
+
+		It("posts the group to /Groups", func() {
+			group, err := subject.CreateGroup("admin-token", uaa.Group{
+				DisplayName: "cloud_controller.admin",
+				Description: "CC admins",
+				Members:     []uaa.GroupMember{{Value: "user-guid", Type: uaa.GroupMemberUser}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(group.ID).To(Equal("group-guid"))
+
+			Expect(requests[0].Method).To(Equal("POST"))
+			Expect(requests[0].URL.Path).To(Equal("/Groups"))
+			Expect(bodies[0]).To(MatchJSON(`{
+				"displayName": "cloud_controller.admin",
+				"description": "CC admins",
+				"members": [{"value": "user-guid", "type": "USER"}]
+			}`))
+		})
+
+		Context("when the group already exists", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusConflict)
+					w.Write([]byte(`{"error":"scim_resource_already_exists","error_description":"A group with displayName: cloud_controller.admin already exists."}`))
+				}
+			})
+
+			It("returns a conflict error", func() {
+				_, err := subject.CreateGroup("admin-token", uaa.Group{DisplayName: "cloud_controller.admin"})
+				Expect(err).To(MatchError("UAA Error: A group with displayName: cloud_controller.admin already exists. (scim_resource_already_exists)"))
+
+				Expect(uaa.IsConflict(err)).To(BeTrue())
+
+				uaaErr, ok := err.(*uaa.Error)
+				Expect(ok).To(BeTrue())
+				Expect(uaaErr.StatusCode).To(Equal(http.StatusConflict))
+				Expect(uaaErr.ErrorCode).To(Equal("scim_resource_already_exists"))
+			})
+		})
+	})
+
+	Describe("GetGroup", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"id":"group-guid","displayName":"openid","members":[{"value":"nested-guid","type":"GROUP","origin":"uaa"}],"meta":{"version":2}}`))
+			}
+		})
+
+		It("gets the group by id", func() {
+			group, err := subject.GetGroup("admin-token", "group-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(group.DisplayName).To(Equal("openid"))
+			Expect(group.Members).To(Equal([]uaa.GroupMember{{Value: "nested-guid", Type: uaa.GroupMemberGroup, Origin: "uaa"}}))
+			Expect(group.Meta.Version).To(Equal(2))
+			Expect(requests[0].URL.Path).To(Equal("/Groups/group-guid"))
+		})
+	})
+
+	Describe("FindAllGroups", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("startIndex") == "1" {
+					w.Write([]byte(`{"resources":[{"id":"group-1"},{"id":"group-2"}],"startIndex":1,"totalResults":3}`))
+					return
+				}
+				w.Write([]byte(`{"resources":[{"id":"group-3"}],"startIndex":3,"totalResults":3}`))
+			}
+		})
+
+		It("pages through the groups", func() {
+			groups, err := subject.FindAllGroups("admin-token", uaa.ScimQuery{Filter: `displayName sw "cloud_controller."`})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(groups).To(Equal([]uaa.Group{{ID: "group-1"}, {ID: "group-2"}, {ID: "group-3"}}))
+
+			Expect(requests).To(HaveLen(2))
+			Expect(requests[1].URL.Query().Get("startIndex")).To(Equal("3"))
+			Expect(requests[1].URL.Query().Get("filter")).To(Equal(`displayName sw "cloud_controller."`))
+		})
+	})
+
+	Describe("UpdateGroup", func() {
+		It("puts the group using its version", func() {
+			_, err := subject.UpdateGroup("admin-token", uaa.Group{ID: "group-guid", DisplayName: "renamed", Meta: &uaa.Meta{Version: 5}})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests[0].Method).To(Equal("PUT"))
+			Expect(requests[0].URL.Path).To(Equal("/Groups/group-guid"))
+			Expect(requests[0].Header.Get("If-Match")).To(Equal("5"))
+		})
+
+		It("requires a version", func() {
+			_, err := subject.UpdateGroup("admin-token", uaa.Group{ID: "group-guid", DisplayName: "renamed"})
+			Expect(err).To(MatchError("Missing group version"))
+			Expect(requests).To(BeEmpty())
+		})
+
+		Context("when the version is stale", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusPreconditionFailed)
+					w.Write([]byte(`{"error":"scim_resource_conflict","error_description":"Invalid version"}`))
+				}
+			})
+
+			It("returns a version mismatch conflict", func() {
+				_, err := subject.UpdateGroup("admin-token", uaa.Group{ID: "group-guid", Meta: &uaa.Meta{Version: 1}})
+				Expect(uaa.IsConflict(err)).To(BeTrue())
+				Expect(uaa.IsVersionMismatch(err)).To(BeTrue())
+
+				uaaErr, ok := err.(*uaa.Error)
+				Expect(ok).To(BeTrue())
+				Expect(uaaErr.StatusCode).To(Equal(http.StatusPreconditionFailed))
+			})
+		})
+	})
+
+	Describe("DeleteGroup", func() {
+		It("deletes the group", func() {
+			Expect(subject.DeleteGroup("admin-token", "group-guid")).To(Succeed())
+			Expect(requests[0].Method).To(Equal("DELETE"))
+			Expect(requests[0].URL.Path).To(Equal("/Groups/group-guid"))
+		})
+
+		It("requires an id", func() {
+			Expect(subject.DeleteGroup("admin-token", "")).To(MatchError("Missing group id"))
+		})
+	})
+
+	Describe("members", func() {
+		It("adds users by default", func() {
+			_, err := subject.AddGroupMember("admin-token", "group-guid", uaa.GroupMember{Value: "user-guid"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests[0].Method).To(Equal("POST"))
+			Expect(requests[0].URL.Path).To(Equal("/Groups/group-guid/members"))
+			Expect(bodies[0]).To(MatchJSON(`{"value":"user-guid","type":"USER"}`))
+		})
+
+		It("adds nested groups", func() {
+			_, err := subject.AddGroupMember("admin-token", "group-guid", uaa.GroupMember{Value: "nested-guid", Type: uaa.GroupMemberGroup})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bodies[0]).To(MatchJSON(`{"value":"nested-guid","type":"GROUP"}`))
+		})
+
+		It("removes members", func() {
+			Expect(subject.RemoveGroupMember("admin-token", "group-guid", "user-guid")).To(Succeed())
+			Expect(requests[0].Method).To(Equal("DELETE"))
+			Expect(requests[0].URL.Path).To(Equal("/Groups/group-guid/members/user-guid"))
+		})
+
+		Context("when listing", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`[{"value":"user-guid","type":"USER","origin":"uaa"},{"value":"nested-guid","type":"GROUP","origin":"uaa"}]`))
+				}
+			})
+
+			It("lists the members", func() {
+				members, err := subject.GroupMembers("admin-token", "group-guid")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(members).To(Equal([]uaa.GroupMember{
+					{Value: "user-guid", Type: "USER", Origin: "uaa"},
+					{Value: "nested-guid", Type: "GROUP", Origin: "uaa"},
+				}))
+				Expect(requests[0].URL.Path).To(Equal("/Groups/group-guid/members"))
+			})
+		})
+
+		Context("when the member already exists", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusConflict)
+					w.Write([]byte(`{"error":"member_already_exists","error_description":"Member user-guid already exists in group group-guid"}`))
+				}
+			})
+
+			It("returns a conflict error", func() {
+				_, err := subject.AddGroupMember("admin-token", "group-guid", uaa.GroupMember{Value: "user-guid"})
+				Expect(uaa.IsConflict(err)).To(BeTrue())
+				Expect(uaa.IsVersionMismatch(err)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("external group mappings", func() {
+		It("maps an external group", func() {
+			_, err := subject.MapExternalGroup("admin-token", uaa.GroupMapping{
+				GroupID:       "group-guid",
+				ExternalGroup: "cn=admins,ou=groups,dc=example,dc=com",
+				Origin:        "ldap",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests[0].Method).To(Equal("POST"))
+			Expect(requests[0].URL.Path).To(Equal("/Groups/External"))
+			Expect(bodies[0]).To(MatchJSON(`{"groupId":"group-guid","externalGroup":"cn=admins,ou=groups,dc=example,dc=com","origin":"ldap"}`))
+		})
+
+		It("unmaps an external group", func() {
+			err := subject.UnmapExternalGroup("admin-token", uaa.GroupMapping{
+				GroupID:       "group-guid",
+				ExternalGroup: "admins/saml",
+				Origin:        "okta",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests[0].Method).To(Equal("DELETE"))
+			Expect(requests[0].URL.EscapedPath()).To(Equal("/Groups/External/groupId/group-guid/externalGroup/admins%2Fsaml/origin/okta"))
+		})
+
+		It("requires the full mapping to unmap", func() {
+			err := subject.UnmapExternalGroup("admin-token", uaa.GroupMapping{ExternalGroup: "admins", Origin: "okta"})
+			Expect(err).To(MatchError("Missing group id"))
+
+			err = subject.UnmapExternalGroup("admin-token", uaa.GroupMapping{GroupID: "group-guid", Origin: "okta"})
+			Expect(err).To(MatchError("Missing external group"))
+
+			err = subject.UnmapExternalGroup("admin-token", uaa.GroupMapping{GroupID: "group-guid", ExternalGroup: "admins"})
+			Expect(err).To(MatchError("Missing origin"))
+
+			Expect(requests).To(BeEmpty())
+		})
+
+		Context("when listing", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`{"resources":[{"groupId":"group-guid","displayName":"admins","externalGroup":"cn=admins","origin":"ldap"}],"startIndex":1,"itemsPerPage":100,"totalResults":1}`))
+				}
+			})
+
+			It("lists the mappings", func() {
+				page, err := subject.ExternalGroupMappings("admin-token", uaa.ScimQuery{Filter: uaa.ScimEqual("origin", "ldap")})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(page.Resources).To(Equal([]uaa.GroupMapping{
+					{GroupID: "group-guid", DisplayName: "admins", ExternalGroup: "cn=admins", Origin: "ldap"},
+				}))
+				Expect(requests[0].URL.Query().Get("filter")).To(Equal(`origin eq "ldap"`))
+			})
+		})
+	})
+})
@@ -0,0 +1,88 @@
+package uaa
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const (
+	ProviderTypeUAA      = "uaa"
+	ProviderTypeLDAP     = "ldap"
+	ProviderTypeSAML     = "saml"
+	ProviderTypeOAuth    = "oauth2.0"
+	ProviderTypeOIDC     = "oidc1.0"
+	ProviderTypeKeystone = "keystone"
+)
+
+type IdentityProvider struct {
+	ID             string          `json:"id"`
+	OriginKey      string          `json:"originKey"`
+	Name           string          `json:"name"`
+	Type           string          `json:"type"`
+	Active         bool            `json:"active"`
+	IdentityZoneID string          `json:"identityZoneId"`
+	Config         json.RawMessage `json:"config,omitempty"`
+	Version        int             `json:"version"`
+	Created        int64           `json:"created"`
+	LastModified   int64           `json:"last_modified"`
+}
+
+func (p IdentityProvider) IsSSO() bool {
+	switch p.Type {
+	case ProviderTypeSAML, ProviderTypeOAuth, ProviderTypeOIDC:
+		return true
+	}
+	return false
+}
+
+type LoginFlow string
+
+const (
+	LoginFlowPassword          LoginFlow = "password"
+	LoginFlowPasscode          LoginFlow = "passcode"
+	LoginFlowAuthorizationCode LoginFlow = "authorization_code"
+)
+
+func (c *Client) IdentityProviders(accessToken string) ([]IdentityProvider, error) {
+	return c.IdentityProvidersWithContext(context.Background(), accessToken)
+}
+
+func (c *Client) IdentityProvidersWithContext(ctx context.Context, accessToken string) ([]IdentityProvider, error) {
+	var providers []IdentityProvider
+	err := c.scimRequest(ctx, "GET", "/identity-providers?rawConfig=false", accessToken, &providers)
+	if err != nil {
+		return nil, err
+	}
+
+	return providers, nil
+}
+
+// LoginFlows lists the ways a user can authenticate against the zone,
+// preferring direct credentials over browser based flows. Providers are
+// optional, listing them requires the idps.read scope. Without login info
+// there are no flows.
+func LoginFlows(info *LoginInfo, providers []IdentityProvider) []LoginFlow {
+	if info == nil {
+		return nil
+	}
+
+	var flows []LoginFlow
+	if info.SupportsPassword() {
+		flows = append(flows, LoginFlowPassword)
+	}
+	if info.SupportsPasscode() {
+		flows = append(flows, LoginFlowPasscode)
+	}
+
+	sso := len(info.IdpDefinitions) > 0
+	for _, provider := range providers {
+		if provider.Active && provider.IsSSO() {
+			sso = true
+		}
+	}
+	if sso {
+		flows = append(flows, LoginFlowAuthorizationCode)
+	}
+
+	return flows
+}
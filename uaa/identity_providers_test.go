@@ -0,0 +1,106 @@
+package uaa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Identity providers", func() {
+	var (
+		server  *httptest.Server
+		subject uaa.Client
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/identity-providers":
+				Expect(r.Header.Get("Authorization")).To(Equal("bearer admin-token"))
+				Expect(r.URL.Query().Get("rawConfig")).To(Equal("false"))
+				w.Write([]byte(`[
+					{"id":"idp-1","originKey":"uaa","name":"uaa","type":"uaa","active":true,"identityZoneId":"uaa","version":0},
+					{"id":"idp-2","originKey":"okta","name":"Okta","type":"saml","active":true,"identityZoneId":"uaa","config":{"metaDataLocation":"https://okta.example.com/metadata"},"version":3}
+				]`))
+			case "/info":
+				w.Write([]byte(`{
+					"app": {"version": "74.0.0"},
+					"zone_name": "tenant",
+					"entityID": "tenant.uaa.example.com",
+					"commit_id": "abc123",
+					"idpDefinitions": {"okta": "https://tenant.uaa.example.com/saml/discovery?idp=okta"},
+					"links": {"login": "https://login.example.com"},
+					"prompts": {"username": ["text", "Email"], "password": ["password", "Password"]}
+				}`))
+			}
+		}))
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("lists the identity providers", func() {
+		providers, err := subject.IdentityProviders("admin-token")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(providers).To(HaveLen(2))
+
+		Expect(providers[0].OriginKey).To(Equal("uaa"))
+		Expect(providers[0].IsSSO()).To(BeFalse())
+
+		Expect(providers[1].ID).To(Equal("idp-2"))
+		Expect(providers[1].Name).To(Equal("Okta"))
+		Expect(providers[1].Type).To(Equal(uaa.ProviderTypeSAML))
+		Expect(providers[1].Version).To(Equal(3))
+		Expect(providers[1].Config).To(MatchJSON(`{"metaDataLocation":"https://okta.example.com/metadata"}`))
+		Expect(providers[1].IsSSO()).To(BeTrue())
+	})
+
+	It("fetches the server info", func() {
+		info, err := subject.Info()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(info.App.Version).To(Equal("74.0.0"))
+		Expect(info.ZoneName).To(Equal("tenant"))
+		Expect(info.EntityID).To(Equal("tenant.uaa.example.com"))
+		Expect(info.CommitID).To(Equal("abc123"))
+		Expect(info.Links["login"]).To(Equal("https://login.example.com"))
+		Expect(info.SupportsPassword()).To(BeTrue())
+		Expect(info.SupportsPasscode()).To(BeFalse())
+	})
+
+	Describe("LoginFlows", func() {
+		It("offers the flows the zone supports", func() {
+			info := &uaa.LoginInfo{Prompts: map[string]uaa.Prompt{
+				"password": {Type: "password", Text: "Password"},
+				"passcode": {Type: "password", Text: "Passcode"},
+			}}
+			Expect(uaa.LoginFlows(info, nil)).To(Equal([]uaa.LoginFlow{uaa.LoginFlowPassword, uaa.LoginFlowPasscode}))
+		})
+
+		It("offers the authorization code flow for sso providers", func() {
+			info := &uaa.LoginInfo{}
+			providers := []uaa.IdentityProvider{
+				{Type: uaa.ProviderTypeOIDC, Active: false},
+				{Type: uaa.ProviderTypeLDAP, Active: true},
+			}
+			Expect(uaa.LoginFlows(info, providers)).To(BeEmpty())
+
+			providers[0].Active = true
+			Expect(uaa.LoginFlows(info, providers)).To(Equal([]uaa.LoginFlow{uaa.LoginFlowAuthorizationCode}))
+
+			info.IdpDefinitions = map[string]string{"okta": "https://uaa.example.com/saml/discovery?idp=okta"}
+			Expect(uaa.LoginFlows(info, nil)).To(Equal([]uaa.LoginFlow{uaa.LoginFlowAuthorizationCode}))
+		})
+
+		It("offers nothing without login info", func() {
+			providers := []uaa.IdentityProvider{{Type: uaa.ProviderTypeOIDC, Active: true}}
+			Expect(uaa.LoginFlows(nil, providers)).To(BeNil())
+		})
+	})
+})
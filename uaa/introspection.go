@@ -0,0 +1,152 @@
+package uaa
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxTokenInfoCacheEntries = 1000
+
+type TokenInfo struct {
+	Active bool `json:"active"`
+	Claims
+}
+
+func (c *Client) CheckToken(token string, scopes ...string) (*TokenInfo, error) {
+	return c.CheckTokenWithContext(context.Background(), token, scopes...)
+}
+
+func (c *Client) CheckTokenWithContext(ctx context.Context, token string, scopes ...string) (*TokenInfo, error) {
+	data := url.Values{
+		"token": {stripTokenType(token)},
+	}
+	if len(scopes) > 0 {
+		data.Set("scopes", strings.Join(scopes, ","))
+	}
+
+	return c.fetchTokenInfo(ctx, "/check_token", data, func(info *TokenInfo) {
+		info.Active = true
+	})
+}
+
+func (c *Client) Introspect(token string) (*TokenInfo, error) {
+	return c.IntrospectWithContext(context.Background(), token)
+}
+
+func (c *Client) IntrospectWithContext(ctx context.Context, token string) (*TokenInfo, error) {
+	data := url.Values{
+		"token": {stripTokenType(token)},
+	}
+
+	return c.fetchTokenInfo(ctx, "/introspect", data, nil)
+}
+
+func (c *Client) fetchTokenInfo(ctx context.Context, path string, data url.Values, onSuccess func(*TokenInfo)) (*TokenInfo, error) {
+	zone := c.zone(ctx)
+	cacheKey := c.endpoint + path + "?" + data.Encode() + "|" + zone.id + "|" + zone.subdomain
+	if info, ok := c.tokenInfoCache.get(cacheKey); ok {
+		return info, nil
+	}
+
+	request, err := c.newClientAuthRequest(ctx, path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	info := new(TokenInfo)
+	err = c.runJSONRequest(request, info)
+	if uaaErr, ok := err.(*Error); ok && uaaErr.ErrorCode == "invalid_token" {
+		c.tokenInfoCache.set(cacheKey, &TokenInfo{}, c.TokenInfoCacheTTL)
+		return &TokenInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if onSuccess != nil {
+		onSuccess(info)
+	}
+
+	ttl := c.TokenInfoCacheTTL
+	if info.Active && info.Exp != 0 {
+		if untilExpiry := time.Until(info.ExpiresAt()); untilExpiry < ttl {
+			ttl = untilExpiry
+		}
+	}
+	c.tokenInfoCache.set(cacheKey, info, ttl)
+
+	return info, nil
+}
+
+type tokenInfoCacheEntry struct {
+	info      TokenInfo
+	expiresAt time.Time
+}
+
+type tokenInfoCache struct {
+	mutex   sync.Mutex
+	entries map[string]tokenInfoCacheEntry
+}
+
+func newTokenInfoCache() *tokenInfoCache {
+	return &tokenInfoCache{entries: map[string]tokenInfoCacheEntry{}}
+}
+
+func (c *tokenInfoCache) get(key string) (*TokenInfo, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	hashedKey := hashKey(key)
+	entry, ok := c.entries[hashedKey]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, hashedKey)
+		return nil, false
+	}
+
+	info := entry.info
+	return &info, true
+}
+
+func (c *tokenInfoCache) set(key string, info *TokenInfo, ttl time.Duration) {
+	if c == nil || ttl <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= maxTokenInfoCacheEntries {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) >= maxTokenInfoCacheEntries {
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+
+	c.entries[hashKey(key)] = tokenInfoCacheEntry{info: *info, expiresAt: now.Add(ttl)}
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,176 @@
+package uaa_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Token introspection", func() {
+	var (
+		server      *httptest.Server
+		httpHandler http.Handler
+		subject     uaa.Client
+		requests    int32
+	)
+
+	BeforeEach(func() {
+		atomic.StoreInt32(&requests, 0)
+	})
+
+	JustBeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			httpHandler.ServeHTTP(w, r)
+		}))
+		subject = uaa.NewClient(server.URL)
+		subject.ClientID = "my-resource-server"
+		subject.ClientSecret = "my-secret"
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("CheckToken", func() {
+		Context("when the token is valid", func() {
+			BeforeEach(func() {
+				httpHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.Method).To(Equal("POST"))
+					Expect(r.URL.Path).To(Equal("/check_token"))
+
+					clientID, clientSecret, ok := r.BasicAuth()
+					Expect(ok).To(BeTrue())
+					Expect(clientID).To(Equal("my-resource-server"))
+					Expect(clientSecret).To(Equal("my-secret"))
+
+					Expect(r.FormValue("token")).To(Equal("my-token"))
+					Expect(r.FormValue("scopes")).To(Equal("cloud_controller.read,openid"))
+
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"user_id":"some-guid","user_name":"admin","client_id":"cf","scope":["cloud_controller.read","openid"],"aud":["cloud_controller"],"exp":1444000600}`))
+				})
+			})
+
+			It("returns the token info", func() {
+				info, err := subject.CheckToken("bearer my-token", "cloud_controller.read", "openid")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(info.Active).To(BeTrue())
+				Expect(info.UserName).To(Equal("admin"))
+				Expect(info.UserID).To(Equal("some-guid"))
+				Expect(info.HasScope("openid")).To(BeTrue())
+				Expect(info.ExpiresAt()).To(Equal(time.Unix(1444000600, 0)))
+				Expect(info.Audience).To(Equal(uaa.Audience{"cloud_controller"}))
+			})
+		})
+
+		Context("when the token is not valid", func() {
+			BeforeEach(func() {
+				httpHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error":"invalid_token","error_description":"Token has expired"}`))
+				})
+			})
+
+			It("returns an inactive token", func() {
+				info, err := subject.CheckToken("my-token")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(info.Active).To(BeFalse())
+			})
+		})
+
+		Context("when the client is not allowed to check tokens", func() {
+			BeforeEach(func() {
+				httpHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"error":"access_denied","error_description":"Access is denied"}`))
+				})
+			})
+
+			It("returns the uaa error", func() {
+				_, err := subject.CheckToken("my-token")
+				Expect(err).To(MatchError("UAA Error: Access is denied (access_denied)"))
+			})
+		})
+	})
+
+	Describe("Introspect", func() {
+		var active bool
+
+		BeforeEach(func() {
+			active = true
+			httpHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Method).To(Equal("POST"))
+				Expect(r.URL.Path).To(Equal("/introspect"))
+				Expect(r.FormValue("token")).To(Equal("my-token"))
+
+				w.Header().Set("Content-Type", "application/json")
+				if !active {
+					w.Write([]byte(`{"active":false}`))
+					return
+				}
+
+				exp := time.Now().Add(time.Hour).Unix()
+				fmt.Fprintf(w, `{"active":true,"user_name":"admin","email":"admin@example.com","scope":["openid"],"exp":%d}`, exp)
+			})
+		})
+
+		It("returns the token info", func() {
+			info, err := subject.Introspect("my-token")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Active).To(BeTrue())
+			Expect(info.Email).To(Equal("admin@example.com"))
+			Expect(info.HasScope("openid")).To(BeTrue())
+		})
+
+		Context("when the token is not active", func() {
+			BeforeEach(func() {
+				active = false
+			})
+
+			It("returns an inactive token", func() {
+				info, err := subject.Introspect("my-token")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(info.Active).To(BeFalse())
+			})
+		})
+
+		Describe("caching", func() {
+			It("reuses the result within the TTL", func() {
+				for i := 0; i < 3; i++ {
+					info, err := subject.Introspect("my-token")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(info.Active).To(BeTrue())
+				}
+				Expect(atomic.LoadInt32(&requests)).To(BeEquivalentTo(1))
+			})
+
+			It("asks again once the TTL expired", func() {
+				subject.TokenInfoCacheTTL = 50 * time.Millisecond
+				_, err := subject.Introspect("my-token")
+				Expect(err).ToNot(HaveOccurred())
+
+				time.Sleep(100 * time.Millisecond)
+				_, err = subject.Introspect("my-token")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(atomic.LoadInt32(&requests)).To(BeEquivalentTo(2))
+			})
+
+			It("can be disabled", func() {
+				subject.TokenInfoCacheTTL = 0
+				subject.Introspect("my-token")
+				subject.Introspect("my-token")
+				Expect(atomic.LoadInt32(&requests)).To(BeEquivalentTo(2))
+			})
+		})
+	})
+})
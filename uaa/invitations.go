@@ -0,0 +1,176 @@
+package uaa
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+type InviteOptions struct {
+	ClientID    string
+	RedirectURI string
+}
+
+type Invite struct {
+	Email        string `json:"email"`
+	UserID       string `json:"userId"`
+	Origin       string `json:"origin"`
+	Success      bool   `json:"success"`
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+	InviteLink   string `json:"inviteLink"`
+}
+
+type InviteResult struct {
+	NewInvites    []Invite `json:"new_invites"`
+	FailedInvites []Invite `json:"failed_invites"`
+}
+
+type PasswordResetCode struct {
+	Code   string `json:"code"`
+	UserID string `json:"user_id"`
+}
+
+type PasswordChange struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Code     string `json:"code"`
+}
+
+func (r *InviteResult) Links() map[string]string {
+	links := map[string]string{}
+	for _, invite := range r.NewInvites {
+		links[invite.Email] = invite.InviteLink
+	}
+
+	return links
+}
+
+func (c *Client) InviteUsers(accessToken string, options InviteOptions, emails ...string) (*InviteResult, error) {
+	return c.InviteUsersWithContext(context.Background(), accessToken, options, emails...)
+}
+
+func (c *Client) InviteUsersWithContext(ctx context.Context, accessToken string, options InviteOptions, emails ...string) (*InviteResult, error) {
+	if len(emails) == 0 {
+		return nil, errors.New("Missing emails")
+	}
+
+	body := map[string][]string{"emails": emails}
+	result := new(InviteResult)
+	err := c.sendScimJSON(ctx, "POST", "/invite_users"+options.encode(c.ClientID), accessToken, body, "", result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) RequestPasswordReset(accessToken, username string) (*PasswordResetCode, error) {
+	return c.RequestPasswordResetWithContext(context.Background(), accessToken, username)
+}
+
+func (c *Client) RequestPasswordResetWithContext(ctx context.Context, accessToken, username string) (*PasswordResetCode, error) {
+	if username == "" {
+		return nil, errors.New("Missing username")
+	}
+
+	request, err := c.newRequest(ctx, "POST", "/password_resets", strings.NewReader(username))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	setBearerAuth(request, accessToken)
+
+	code := new(PasswordResetCode)
+	err = c.runJSONRequest(request, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return code, nil
+}
+
+func (c *Client) ResetPassword(accessToken, code, newPassword string) (*PasswordChange, error) {
+	return c.ResetPasswordWithContext(context.Background(), accessToken, code, newPassword)
+}
+
+func (c *Client) ResetPasswordWithContext(ctx context.Context, accessToken, code, newPassword string) (*PasswordChange, error) {
+	if code == "" {
+		return nil, errors.New("Missing reset code")
+	}
+
+	body := map[string]string{
+		"code":         code,
+		"new_password": newPassword,
+	}
+
+	change := new(PasswordChange)
+	err := c.sendScimJSON(ctx, "POST", "/password_change", accessToken, body, "", change)
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+func (c *Client) VerificationLink(accessToken, userID, redirectURI string) (string, error) {
+	return c.VerificationLinkWithContext(context.Background(), accessToken, userID, redirectURI)
+}
+
+func (c *Client) VerificationLinkWithContext(ctx context.Context, accessToken, userID, redirectURI string) (string, error) {
+	if userID == "" {
+		return "", errors.New("Missing user id")
+	}
+
+	path := "/Users/" + url.PathEscape(userID) + "/verify-link"
+	if redirectURI != "" {
+		path += "?" + url.Values{"redirect_uri": {redirectURI}}.Encode()
+	}
+
+	var response struct {
+		VerifyLink string `json:"verify_link"`
+	}
+	err := c.scimRequest(ctx, "GET", path, accessToken, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.VerifyLink, nil
+}
+
+func (c *Client) VerifyUser(accessToken, userID string) (*User, error) {
+	return c.VerifyUserWithContext(context.Background(), accessToken, userID)
+}
+
+func (c *Client) VerifyUserWithContext(ctx context.Context, accessToken, userID string) (*User, error) {
+	if userID == "" {
+		return nil, errors.New("Missing user id")
+	}
+
+	user := new(User)
+	err := c.scimRequest(ctx, "GET", "/Users/"+url.PathEscape(userID)+"/verify", accessToken, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (o InviteOptions) encode(defaultClientID string) string {
+	values := url.Values{}
+	if o.ClientID != "" {
+		values.Set("client_id", o.ClientID)
+	} else if defaultClientID != "" {
+		values.Set("client_id", defaultClientID)
+	}
+	if o.RedirectURI != "" {
+		values.Set("redirect_uri", o.RedirectURI)
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
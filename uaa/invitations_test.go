@@ -0,0 +1,164 @@
+package uaa_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Invitations and password resets", func() {
+	var (
+		server   *httptest.Server
+		handler  http.HandlerFunc
+		subject  uaa.Client
+		requests []*http.Request
+		bodies   []string
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = nil
+	})
+
+	JustBeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("bearer admin-token"))
+			body, err := ioutil.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+
+			handler(w, r)
+		}))
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("InviteUsers", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{
+					"new_invites": [
+						{"email":"dev1@example.com","userId":"user-1","origin":"uaa","success":true,"inviteLink":"https://uaa.example.com/invitations/accept?code=one"},
+						{"email":"dev2@example.com","userId":"user-2","origin":"uaa","success":true,"inviteLink":"https://uaa.example.com/invitations/accept?code=two"}
+					],
+					"failed_invites": [
+						{"email":"bad","success":false,"errorCode":"invalid.email","errorMessage":"Invalid email"}
+					]
+				}`))
+			}
+		})
+
+		It("returns the invite link per email", func() {
+			result, err := subject.InviteUsers("admin-token", uaa.InviteOptions{RedirectURI: "https://apps.example.com"}, "dev1@example.com", "dev2@example.com", "bad")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(result.Links()).To(Equal(map[string]string{
+				"dev1@example.com": "https://uaa.example.com/invitations/accept?code=one",
+				"dev2@example.com": "https://uaa.example.com/invitations/accept?code=two",
+			}))
+			Expect(result.FailedInvites).To(Equal([]uaa.Invite{
+				{Email: "bad", ErrorCode: "invalid.email", ErrorMessage: "Invalid email"},
+			}))
+
+			Expect(requests[0].Method).To(Equal("POST"))
+			Expect(requests[0].URL.Path).To(Equal("/invite_users"))
+			Expect(requests[0].URL.Query().Get("client_id")).To(Equal("cf"))
+			Expect(requests[0].URL.Query().Get("redirect_uri")).To(Equal("https://apps.example.com"))
+			Expect(bodies[0]).To(MatchJSON(`{"emails":["dev1@example.com","dev2@example.com","bad"]}`))
+		})
+
+		It("can invite through another client", func() {
+			_, err := subject.InviteUsers("admin-token", uaa.InviteOptions{ClientID: "portal"}, "dev1@example.com")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests[0].URL.Query().Get("client_id")).To(Equal("portal"))
+		})
+
+		It("requires emails", func() {
+			_, err := subject.InviteUsers("admin-token", uaa.InviteOptions{})
+			Expect(err).To(MatchError("Missing emails"))
+		})
+	})
+
+	Describe("password reset", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/password_resets":
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"code":"reset-code","user_id":"user-guid"}`))
+				case "/password_change":
+					w.Write([]byte(`{"user_id":"user-guid","username":"marissa","email":"marissa@example.com","code":"autologin-code"}`))
+				}
+			}
+		})
+
+		It("requests a reset code for the username", func() {
+			code, err := subject.RequestPasswordReset("admin-token", "marissa")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(code).To(Equal(&uaa.PasswordResetCode{Code: "reset-code", UserID: "user-guid"}))
+
+			Expect(requests[0].Method).To(Equal("POST"))
+			Expect(bodies[0]).To(Equal("marissa"))
+		})
+
+		It("changes the password with the code", func() {
+			change, err := subject.ResetPassword("admin-token", "reset-code", "n3w-p4ss")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(change.Username).To(Equal("marissa"))
+			Expect(change.Code).To(Equal("autologin-code"))
+
+			Expect(requests[0].Method).To(Equal("POST"))
+			Expect(bodies[0]).To(MatchJSON(`{"code":"reset-code","new_password":"n3w-p4ss"}`))
+		})
+
+		Context("when the code has expired", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					w.Write([]byte(`{"error":"invalid_code","error_description":"Sorry, your reset password link is no longer valid."}`))
+				}
+			})
+
+			It("returns the uaa error", func() {
+				_, err := subject.ResetPassword("admin-token", "reset-code", "n3w-p4ss")
+				Expect(err).To(MatchError("UAA Error: Sorry, your reset password link is no longer valid. (invalid_code)"))
+			})
+		})
+	})
+
+	Describe("account verification", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/Users/user-guid/verify-link":
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"verify_link":"https://uaa.example.com/verify_user?code=abc"}`))
+				case "/Users/user-guid/verify":
+					w.Write([]byte(`{"id":"user-guid","verified":true}`))
+				}
+			}
+		})
+
+		It("returns a verification link", func() {
+			link, err := subject.VerificationLink("admin-token", "user-guid", "https://apps.example.com")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(link).To(Equal("https://uaa.example.com/verify_user?code=abc"))
+			Expect(requests[0].URL.Query().Get("redirect_uri")).To(Equal("https://apps.example.com"))
+		})
+
+		It("verifies the user", func() {
+			user, err := subject.VerifyUser("admin-token", "user-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*user.Verified).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,114 @@
+package uaa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var promptURL = regexp.MustCompile(`https?://[^\s)]+`)
+
+type Prompt struct {
+	Type string
+	Text string
+}
+
+func (p *Prompt) UnmarshalJSON(data []byte) error {
+	var fields []string
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) != 2 {
+		return fmt.Errorf("Invalid prompt: %s", data)
+	}
+
+	p.Type = fields[0]
+	p.Text = fields[1]
+	return nil
+}
+
+type LoginInfo struct {
+	App struct {
+		Version string `json:"version"`
+	} `json:"app"`
+	Prompts        map[string]Prompt `json:"prompts"`
+	Links          map[string]string `json:"links"`
+	ZoneName       string            `json:"zone_name"`
+	EntityID       string            `json:"entityID"`
+	CommitID       string            `json:"commit_id"`
+	Timestamp      string            `json:"timestamp"`
+	IdpDefinitions map[string]string `json:"idpDefinitions"`
+	ShowLoginLinks bool              `json:"showLoginLinks"`
+}
+
+func (c *Client) LoginInfo() (*LoginInfo, error) {
+	return c.LoginInfoWithContext(context.Background())
+}
+
+func (c *Client) LoginInfoWithContext(ctx context.Context) (*LoginInfo, error) {
+	return c.fetchLoginInfo(ctx, "/login")
+}
+
+func (c *Client) Info() (*LoginInfo, error) {
+	return c.InfoWithContext(context.Background())
+}
+
+func (c *Client) InfoWithContext(ctx context.Context) (*LoginInfo, error) {
+	return c.fetchLoginInfo(ctx, "/info")
+}
+
+func (c *Client) fetchLoginInfo(ctx context.Context, path string) (*LoginInfo, error) {
+	request, err := c.newRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	info := new(LoginInfo)
+	err = c.runJSONRequest(request, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (c *Client) PasscodeURL() (string, error) {
+	return c.PasscodeURLWithContext(context.Background())
+}
+
+func (c *Client) PasscodeURLWithContext(ctx context.Context) (string, error) {
+	info, err := c.LoginInfoWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return info.PasscodeURL(c.endpoint), nil
+}
+
+func (i *LoginInfo) SupportsPassword() bool {
+	_, ok := i.Prompts["password"]
+	return ok
+}
+
+func (i *LoginInfo) SupportsPasscode() bool {
+	_, ok := i.Prompts["passcode"]
+	return ok
+}
+
+func (i *LoginInfo) PasscodeURL(uaaEndpoint string) string {
+	if prompt, ok := i.Prompts["passcode"]; ok {
+		if url := promptURL.FindString(prompt.Text); url != "" {
+			return url
+		}
+	}
+
+	if login := i.Links["login"]; login != "" {
+		return strings.TrimSuffix(login, "/") + "/passcode"
+	}
+
+	return strings.TrimSuffix(uaaEndpoint, "/") + "/passcode"
+}
@@ -0,0 +1,113 @@
+package uaa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Login", func() {
+	var (
+		server       *httptest.Server
+		subject      uaa.Client
+		loginPayload string
+	)
+
+	BeforeEach(func() {
+		loginPayload = `{
+			"app": {"version": "4.7.0"},
+			"links": {"uaa": "https://uaa.example.com", "passwd": "/forgot_password", "login": "https://login.example.com"},
+			"zone_name": "uaa",
+			"prompts": {
+				"username": ["text", "Email"],
+				"password": ["password", "Password"],
+				"passcode": ["password", "Temporary Authentication Code ( Get one at https://login.example.com/passcode )"]
+			}
+		}`
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/login":
+				Expect(r.Header.Get("Accept")).To(Equal("application/json"))
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(loginPayload))
+			case "/oauth/token":
+				Expect(r.FormValue("grant_type")).To(Equal("password"))
+				Expect(r.FormValue("passcode")).To(Equal("my-passcode"))
+				Expect(r.FormValue("username")).To(BeEmpty())
+				w.Write([]byte(`{"access_token":"sso-access-token","refresh_token":"sso-refresh-token","token_type":"bearer"}`))
+			}
+		}))
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("LoginInfo", func() {
+		It("returns the login prompts", func() {
+			info, err := subject.LoginInfo()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(info.Prompts["username"]).To(Equal(uaa.Prompt{Type: "text", Text: "Email"}))
+			Expect(info.Prompts["password"]).To(Equal(uaa.Prompt{Type: "password", Text: "Password"}))
+			Expect(info.SupportsPasscode()).To(BeTrue())
+			Expect(info.SupportsPassword()).To(BeTrue())
+		})
+
+		It("returns the links and zone details", func() {
+			info, err := subject.LoginInfo()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(info.App.Version).To(Equal("4.7.0"))
+			Expect(info.ZoneName).To(Equal("uaa"))
+			Expect(info.Links).To(HaveKeyWithValue("passwd", "/forgot_password"))
+		})
+	})
+
+	Describe("PasscodeURL", func() {
+		It("discovers the passcode url from the prompt", func() {
+			passcodeURL, err := subject.PasscodeURL()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(passcodeURL).To(Equal("https://login.example.com/passcode"))
+		})
+
+		Context("when the prompt has no url", func() {
+			BeforeEach(func() {
+				loginPayload = `{"links": {"login": "https://login.example.com/"}, "prompts": {"passcode": ["password", "One Time Code"]}}`
+			})
+
+			It("falls back to the login link", func() {
+				passcodeURL, err := subject.PasscodeURL()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(passcodeURL).To(Equal("https://login.example.com/passcode"))
+			})
+		})
+
+		Context("when there's no login link either", func() {
+			BeforeEach(func() {
+				loginPayload = `{"prompts": {}}`
+			})
+
+			It("falls back to the uaa endpoint", func() {
+				passcodeURL, err := subject.PasscodeURL()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(passcodeURL).To(Equal(server.URL + "/passcode"))
+			})
+		})
+	})
+
+	Describe("AuthenticateWithPasscode", func() {
+		It("sends the passcode with the password grant", func() {
+			tokens, err := subject.AuthenticateWithPasscode("my-passcode")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokens.AccessToken).To(Equal("sso-access-token"))
+			Expect(tokens.RefreshToken).To(Equal("sso-refresh-token"))
+		})
+	})
+})
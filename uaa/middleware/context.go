@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/tscolari/cfapi/uaa"
+)
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, claims *uaa.Claims) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+func ClaimsFromContext(ctx context.Context) (*uaa.Claims, bool) {
+	claims, ok := ctx.Value(contextKey{}).(*uaa.Claims)
+	return claims, ok
+}
@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/tscolari/cfapi/uaa"
+)
+
+var (
+	ErrMissingToken      = errors.New("Missing bearer token")
+	ErrInactiveToken     = errors.New("Token is not active")
+	ErrInsufficientScope = errors.New("Insufficient scope")
+	ErrInvalidAudience   = errors.New("Invalid token audience")
+)
+
+type Validator interface {
+	ValidateToken(ctx context.Context, token string) (*uaa.Claims, error)
+}
+
+type ValidatorFunc func(ctx context.Context, token string) (*uaa.Claims, error)
+
+func (f ValidatorFunc) ValidateToken(ctx context.Context, token string) (*uaa.Claims, error) {
+	return f(ctx, token)
+}
+
+func LocalValidator(verifier *uaa.Verifier) Validator {
+	return ValidatorFunc(verifier.VerifyWithContext)
+}
+
+func RemoteValidator(client *uaa.Client) Validator {
+	return ValidatorFunc(func(ctx context.Context, token string) (*uaa.Claims, error) {
+		info, err := client.CheckTokenWithContext(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if !info.Active {
+			return nil, ErrInactiveToken
+		}
+		return &info.Claims, nil
+	})
+}
+
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, statusCode int, err error)
+
+type Authenticator struct {
+	validator      Validator
+	RequiredScopes []string
+	Audiences      []string
+	ErrorHandler   ErrorHandler
+	// OnError is told about every rejected request with the underlying
+	// error, which isn't sent to the caller. It is optional.
+	OnError func(r *http.Request, statusCode int, err error)
+}
+
+func NewAuthenticator(validator Validator) *Authenticator {
+	return &Authenticator{
+		validator:    validator,
+		ErrorHandler: DefaultErrorHandler,
+	}
+}
+
+func (a *Authenticator) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := BearerToken(r)
+		if err != nil {
+			a.reject(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		claims, err := a.validator.ValidateToken(r.Context(), token)
+		if err != nil {
+			a.reject(w, r, validationStatusCode(err), err)
+			return
+		}
+
+		err = a.authorize(claims)
+		if err != nil {
+			a.reject(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), claims)))
+	})
+}
+
+func (a *Authenticator) reject(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	if a.OnError != nil {
+		a.OnError(r, statusCode, err)
+	}
+
+	a.ErrorHandler(w, r, statusCode, err)
+}
+
+// validationStatusCode tells invalid tokens apart from failures to reach or
+// talk to UAA, so that an outage isn't reported to callers as a bad token.
+func validationStatusCode(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || errors.Is(err, uaa.ErrKeysUnavailable) {
+		return http.StatusServiceUnavailable
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return http.StatusServiceUnavailable
+	}
+
+	var uaaErr *uaa.Error
+	if errors.As(err, &uaaErr) {
+		switch {
+		case uaaErr.StatusCode >= 500:
+			return http.StatusServiceUnavailable
+		case uaaErr.StatusCode == http.StatusUnauthorized || uaaErr.StatusCode == http.StatusForbidden:
+			return http.StatusInternalServerError
+		}
+	}
+
+	return http.StatusUnauthorized
+}
+
+func (a *Authenticator) authorize(claims *uaa.Claims) error {
+	for _, scope := range a.RequiredScopes {
+		if !claims.HasScope(scope) {
+			return ErrInsufficientScope
+		}
+	}
+
+	if len(a.Audiences) == 0 {
+		return nil
+	}
+
+	for _, audience := range a.Audiences {
+		if claims.Audience.Contains(audience) {
+			return nil
+		}
+	}
+
+	return ErrInvalidAudience
+}
+
+func BearerToken(r *http.Request) (string, error) {
+	header := strings.TrimSpace(r.Header.Get("Authorization"))
+	if len(header) < 7 || !strings.EqualFold(header[:7], "bearer ") {
+		return "", ErrMissingToken
+	}
+
+	token := strings.TrimSpace(header[7:])
+	if token == "" {
+		return "", ErrMissingToken
+	}
+
+	return token, nil
+}
+
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	var code, description string
+	switch {
+	case err == ErrMissingToken:
+		code, description = "invalid_token", "Missing bearer token"
+	case statusCode == http.StatusForbidden:
+		code, description = "insufficient_scope", "The token does not grant access to this resource"
+	case statusCode == http.StatusServiceUnavailable:
+		code, description = "temporarily_unavailable", "Unable to validate the token at this time"
+	case statusCode >= 500:
+		code, description = "server_error", "Unable to validate the token"
+	default:
+		code, description = "invalid_token", "The token is invalid or has expired"
+	}
+
+	if err == ErrMissingToken {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="uaa"`)
+	} else if statusCode < 500 {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="uaa", error="%s", error_description="%s"`, code, description))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
@@ -0,0 +1,271 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/tscolari/cfapi/uaa"
+	"github.com/tscolari/cfapi/uaa/middleware"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authenticator", func() {
+	var (
+		validator     middleware.ValidatorFunc
+		authenticator *middleware.Authenticator
+		handler       http.Handler
+		request       *http.Request
+		recorder      *httptest.ResponseRecorder
+		seenClaims    *uaa.Claims
+		seenToken     string
+	)
+
+	BeforeEach(func() {
+		seenClaims = nil
+		seenToken = ""
+		validator = func(ctx context.Context, token string) (*uaa.Claims, error) {
+			seenToken = token
+			if token != "valid-token" {
+				return nil, errors.New("Token has expired")
+			}
+			return &uaa.Claims{UserName: "admin", Scope: []string{"dashboard.read"}, Audience: uaa.Audience{"dashboard"}}, nil
+		}
+
+		request = httptest.NewRequest("GET", "/dashboard", nil)
+		request.Header.Set("Authorization", "bearer valid-token")
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		authenticator = middleware.NewAuthenticator(validator)
+	})
+
+	serve := func() {
+		handler = authenticator.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := middleware.ClaimsFromContext(r.Context())
+			Expect(ok).To(BeTrue())
+			seenClaims = claims
+			w.WriteHeader(http.StatusOK)
+		}))
+		handler.ServeHTTP(recorder, request)
+	}
+
+	It("validates the bearer token and puts the claims in the context", func() {
+		serve()
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(seenToken).To(Equal("valid-token"))
+		Expect(seenClaims.UserName).To(Equal("admin"))
+	})
+
+	It("accepts the scheme in any case", func() {
+		request.Header.Set("Authorization", "Bearer valid-token")
+		serve()
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+	})
+
+	Context("when there's no bearer token", func() {
+		BeforeEach(func() {
+			request.Header.Set("Authorization", "Basic Y2Y6")
+		})
+
+		It("responds with unauthorized", func() {
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(Equal(`Bearer realm="uaa"`))
+			Expect(seenClaims).To(BeNil())
+		})
+	})
+
+	Context("when the token is not valid", func() {
+		BeforeEach(func() {
+			request.Header.Set("Authorization", "bearer expired-token")
+		})
+
+		It("responds with unauthorized without echoing the validation error", func() {
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(ContainSubstring(`error="invalid_token"`))
+			Expect(recorder.Header().Get("WWW-Authenticate")).ToNot(ContainSubstring("Token has expired"))
+
+			var body map[string]string
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &body)).To(Succeed())
+			Expect(body).To(Equal(map[string]string{"error": "invalid_token", "error_description": "The token is invalid or has expired"}))
+		})
+	})
+
+	Context("when the token can't be validated", func() {
+		var validationErr error
+
+		BeforeEach(func() {
+			validator = func(ctx context.Context, token string) (*uaa.Claims, error) {
+				return nil, validationErr
+			}
+		})
+
+		It("responds with service unavailable when UAA is down", func() {
+			validationErr = &uaa.Error{StatusCode: http.StatusBadGateway, Description: "Bad Gateway"}
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(BeEmpty())
+
+			var body map[string]string
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &body)).To(Succeed())
+			Expect(body).To(Equal(map[string]string{"error": "temporarily_unavailable", "error_description": "Unable to validate the token at this time"}))
+		})
+
+		It("responds with service unavailable when the token keys can't be fetched", func() {
+			validationErr = fmt.Errorf("%w: connection refused", uaa.ErrKeysUnavailable)
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+		})
+
+		It("responds with service unavailable when the request times out", func() {
+			validationErr = &url.Error{Op: "Post", URL: "https://uaa.example.com/check_token", Err: context.DeadlineExceeded}
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+		})
+
+		It("reports the underlying error to OnError", func() {
+			validationErr = &uaa.Error{StatusCode: http.StatusBadGateway, Description: "Bad Gateway"}
+
+			var reported error
+			var reportedStatus int
+			authenticator.OnError = func(r *http.Request, statusCode int, err error) {
+				reportedStatus = statusCode
+				reported = err
+			}
+
+			serve()
+			Expect(reportedStatus).To(Equal(http.StatusServiceUnavailable))
+			Expect(reported).To(Equal(validationErr))
+		})
+
+		It("responds with internal server error when UAA rejects the client", func() {
+			validationErr = &uaa.Error{StatusCode: http.StatusUnauthorized, ErrorCode: "unauthorized", Description: "Bad credentials"}
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).ToNot(ContainSubstring("Bad credentials"))
+		})
+	})
+
+	Context("when scopes are required", func() {
+		It("allows tokens with all the scopes", func() {
+			authenticator.RequiredScopes = []string{"dashboard.read"}
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("forbids tokens missing a scope", func() {
+			authenticator.RequiredScopes = []string{"dashboard.read", "dashboard.write"}
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(ContainSubstring(`error="insufficient_scope"`))
+			Expect(seenClaims).To(BeNil())
+		})
+	})
+
+	Context("when audiences are required", func() {
+		It("allows tokens for one of the audiences", func() {
+			authenticator.Audiences = []string{"other", "dashboard"}
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("forbids tokens for other audiences", func() {
+			authenticator.Audiences = []string{"other"}
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Context("with a custom ErrorHandler", func() {
+		It("delegates the error response", func() {
+			request.Header.Del("Authorization")
+			authenticator.ErrorHandler = func(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+				Expect(err).To(Equal(middleware.ErrMissingToken))
+				http.Redirect(w, r, "/login", http.StatusFound)
+			}
+
+			serve()
+			Expect(recorder.Code).To(Equal(http.StatusFound))
+		})
+	})
+})
+
+var _ = Describe("Validators", func() {
+	var (
+		server *httptest.Server
+		client uaa.Client
+		key    *rsa.PrivateKey
+	)
+
+	BeforeEach(func() {
+		var err error
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/token_keys":
+				json.NewEncoder(w).Encode(map[string]interface{}{"keys": []uaa.TokenKey{{
+					Kid: "key-1",
+					Kty: "RSA",
+					N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				}}})
+			case "/check_token":
+				if r.FormValue("token") != "remote-token" {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error":"invalid_token","error_description":"Invalid token"}`))
+					return
+				}
+				w.Write([]byte(`{"user_name":"remote-user","scope":["openid"]}`))
+			}
+		}))
+		client = uaa.NewClientWithHTTPClient(server.URL, server.Client())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("validates tokens locally with the token keys", func() {
+		payload, err := json.Marshal(map[string]interface{}{"user_name": "local-user", "exp": time.Now().Add(time.Hour).Unix()})
+		Expect(err).ToNot(HaveOccurred())
+		signingInput := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"key-1"}`)) + "." + base64.RawURLEncoding.EncodeToString(payload)
+		digest := sha256.Sum256([]byte(signingInput))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		Expect(err).ToNot(HaveOccurred())
+
+		validator := middleware.LocalValidator(uaa.NewVerifier(&client, ""))
+		claims, err := validator.ValidateToken(context.Background(), signingInput+"."+base64.RawURLEncoding.EncodeToString(signature))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(claims.UserName).To(Equal("local-user"))
+	})
+
+	It("validates tokens remotely with check_token", func() {
+		validator := middleware.RemoteValidator(&client)
+
+		claims, err := validator.ValidateToken(context.Background(), "remote-token")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(claims.UserName).To(Equal("remote-user"))
+
+		_, err = validator.ValidateToken(context.Background(), "other-token")
+		Expect(err).To(Equal(middleware.ErrInactiveToken))
+	})
+})
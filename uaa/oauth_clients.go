@@ -0,0 +1,290 @@
+package uaa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+)
+
+type OAuthClient struct {
+	ClientID             string      `json:"client_id"`
+	ClientSecret         string      `json:"client_secret,omitempty"`
+	Name                 string      `json:"name,omitempty"`
+	Scope                []string    `json:"scope,omitempty"`
+	ResourceIDs          []string    `json:"resource_ids,omitempty"`
+	AuthorizedGrantTypes []string    `json:"authorized_grant_types,omitempty"`
+	RedirectURI          []string    `json:"redirect_uri,omitempty"`
+	Authorities          []string    `json:"authorities,omitempty"`
+	AutoApprove          AutoApprove `json:"autoapprove,omitempty"`
+	AllowedProviders     []string    `json:"allowedproviders,omitempty"`
+	RequiredUserGroups   []string    `json:"required_user_groups,omitempty"`
+	AccessTokenValidity  int         `json:"access_token_validity,omitempty"`
+	RefreshTokenValidity int         `json:"refresh_token_validity,omitempty"`
+	LastModified         int64       `json:"lastModified,omitempty"`
+}
+
+// AutoApprove lists the scopes approved without prompting the user. UAA
+// reports "all scopes" as a plain true, which is decoded as ["true"].
+type AutoApprove []string
+
+func (a *AutoApprove) UnmarshalJSON(data []byte) error {
+	var approveAll bool
+	if json.Unmarshal(data, &approveAll) == nil {
+		*a = nil
+		if approveAll {
+			*a = AutoApprove{"true"}
+		}
+		return nil
+	}
+
+	var scope string
+	if json.Unmarshal(data, &scope) == nil {
+		*a = AutoApprove{scope}
+		return nil
+	}
+
+	var scopes []string
+	err := json.Unmarshal(data, &scopes)
+	if err != nil {
+		return err
+	}
+
+	*a = scopes
+	return nil
+}
+
+type OAuthClientsPage struct {
+	Resources    []OAuthClient `json:"resources"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	TotalResults int           `json:"totalResults"`
+}
+
+const (
+	ClientCreated   = "created"
+	ClientUpdated   = "updated"
+	ClientUnchanged = "unchanged"
+)
+
+type ClientChange struct {
+	ClientID string
+	Action   string
+}
+
+func (c *Client) CreateOAuthClient(accessToken string, client OAuthClient) (*OAuthClient, error) {
+	return c.CreateOAuthClientWithContext(context.Background(), accessToken, client)
+}
+
+func (c *Client) CreateOAuthClientWithContext(ctx context.Context, accessToken string, client OAuthClient) (*OAuthClient, error) {
+	if client.ClientID == "" {
+		return nil, errors.New("Missing client id")
+	}
+
+	result := new(OAuthClient)
+	err := c.sendScimJSON(ctx, "POST", "/oauth/clients", accessToken, client, "", result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) GetOAuthClient(accessToken, clientID string) (*OAuthClient, error) {
+	return c.GetOAuthClientWithContext(context.Background(), accessToken, clientID)
+}
+
+func (c *Client) GetOAuthClientWithContext(ctx context.Context, accessToken, clientID string) (*OAuthClient, error) {
+	if clientID == "" {
+		return nil, errors.New("Missing client id")
+	}
+
+	client := new(OAuthClient)
+	err := c.scimRequest(ctx, "GET", "/oauth/clients/"+url.PathEscape(clientID), accessToken, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (c *Client) ListOAuthClients(accessToken string, query ScimQuery) (*OAuthClientsPage, error) {
+	return c.ListOAuthClientsWithContext(context.Background(), accessToken, query)
+}
+
+func (c *Client) ListOAuthClientsWithContext(ctx context.Context, accessToken string, query ScimQuery) (*OAuthClientsPage, error) {
+	page := new(OAuthClientsPage)
+	err := c.scimRequest(ctx, "GET", "/oauth/clients"+query.encode(), accessToken, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (c *Client) ListAllOAuthClients(accessToken string, query ScimQuery) ([]OAuthClient, error) {
+	return c.ListAllOAuthClientsWithContext(context.Background(), accessToken, query)
+}
+
+func (c *Client) ListAllOAuthClientsWithContext(ctx context.Context, accessToken string, query ScimQuery) ([]OAuthClient, error) {
+	var clients []OAuthClient
+	err := findAll(query, func(query ScimQuery) (int, int, error) {
+		page, err := c.ListOAuthClientsWithContext(ctx, accessToken, query)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		clients = append(clients, page.Resources...)
+		return len(page.Resources), page.TotalResults, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+func (c *Client) UpdateOAuthClient(accessToken string, client OAuthClient) (*OAuthClient, error) {
+	return c.UpdateOAuthClientWithContext(context.Background(), accessToken, client)
+}
+
+func (c *Client) UpdateOAuthClientWithContext(ctx context.Context, accessToken string, client OAuthClient) (*OAuthClient, error) {
+	if client.ClientID == "" {
+		return nil, errors.New("Missing client id")
+	}
+	client.ClientSecret = ""
+
+	result := new(OAuthClient)
+	err := c.sendScimJSON(ctx, "PUT", "/oauth/clients/"+url.PathEscape(client.ClientID), accessToken, client, "", result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) DeleteOAuthClient(accessToken, clientID string) error {
+	return c.DeleteOAuthClientWithContext(context.Background(), accessToken, clientID)
+}
+
+func (c *Client) DeleteOAuthClientWithContext(ctx context.Context, accessToken, clientID string) error {
+	if clientID == "" {
+		return errors.New("Missing client id")
+	}
+
+	return c.scimRequest(ctx, "DELETE", "/oauth/clients/"+url.PathEscape(clientID), accessToken, nil)
+}
+
+func (c *Client) ChangeOAuthClientSecret(accessToken, clientID, oldSecret, newSecret string) error {
+	return c.ChangeOAuthClientSecretWithContext(context.Background(), accessToken, clientID, oldSecret, newSecret)
+}
+
+func (c *Client) ChangeOAuthClientSecretWithContext(ctx context.Context, accessToken, clientID, oldSecret, newSecret string) error {
+	if clientID == "" {
+		return errors.New("Missing client id")
+	}
+
+	body := map[string]string{
+		"clientId": clientID,
+		"secret":   newSecret,
+	}
+	if oldSecret != "" {
+		body["oldSecret"] = oldSecret
+	}
+
+	return c.sendScimJSON(ctx, "PUT", "/oauth/clients/"+url.PathEscape(clientID)+"/secret", accessToken, body, "", nil)
+}
+
+// EnsureOAuthClients creates the missing clients and updates the ones whose
+// metadata differs. Fields left empty keep their current value, so a client
+// only needs the fields it manages. Secrets are only set when a client is
+// created.
+func (c *Client) EnsureOAuthClients(accessToken string, clients ...OAuthClient) ([]ClientChange, error) {
+	return c.EnsureOAuthClientsWithContext(context.Background(), accessToken, clients...)
+}
+
+func (c *Client) EnsureOAuthClientsWithContext(ctx context.Context, accessToken string, clients ...OAuthClient) ([]ClientChange, error) {
+	var changes []ClientChange
+	for _, client := range clients {
+		action, err := c.ensureOAuthClient(ctx, accessToken, client)
+		if err != nil {
+			return changes, err
+		}
+
+		changes = append(changes, ClientChange{ClientID: client.ClientID, Action: action})
+	}
+
+	return changes, nil
+}
+
+func (c *Client) ensureOAuthClient(ctx context.Context, accessToken string, client OAuthClient) (string, error) {
+	current, err := c.GetOAuthClientWithContext(ctx, accessToken, client.ClientID)
+	if uaaErr, ok := err.(*Error); ok && uaaErr.StatusCode == http.StatusNotFound {
+		_, err = c.CreateOAuthClientWithContext(ctx, accessToken, client)
+		if err != nil {
+			return "", err
+		}
+		return ClientCreated, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	client = withCurrentFields(client, *current)
+	if sameOAuthClient(*current, client) {
+		return ClientUnchanged, nil
+	}
+
+	_, err = c.UpdateOAuthClientWithContext(ctx, accessToken, client)
+	if err != nil {
+		return "", err
+	}
+	return ClientUpdated, nil
+}
+
+// withCurrentFields fills the fields client leaves empty from current.
+func withCurrentFields(client, current OAuthClient) OAuthClient {
+	desired := reflect.ValueOf(&client).Elem()
+	existing := reflect.ValueOf(current)
+	for i := 0; i < desired.NumField(); i++ {
+		if desired.Field(i).IsZero() {
+			desired.Field(i).Set(existing.Field(i))
+		}
+	}
+
+	return client
+}
+
+func sameOAuthClient(a, b OAuthClient) bool {
+	return reflect.DeepEqual(normalizeOAuthClient(a), normalizeOAuthClient(b))
+}
+
+func normalizeOAuthClient(client OAuthClient) OAuthClient {
+	client.ClientSecret = ""
+	client.LastModified = 0
+	client.Scope = sortedSet(client.Scope)
+	client.ResourceIDs = sortedSet(client.ResourceIDs)
+	client.AuthorizedGrantTypes = sortedSet(client.AuthorizedGrantTypes)
+	client.RedirectURI = sortedSet(client.RedirectURI)
+	client.Authorities = sortedSet(client.Authorities)
+	client.AutoApprove = sortedSet(client.AutoApprove)
+	client.AllowedProviders = sortedSet(client.AllowedProviders)
+	client.RequiredUserGroups = sortedSet(client.RequiredUserGroups)
+	return client
+}
+
+// sortedSet also drops the placeholders UAA stores for empty lists.
+func sortedSet(values []string) []string {
+	var sorted []string
+	for _, value := range values {
+		if value != "uaa.none" && value != "none" {
+			sorted = append(sorted, value)
+		}
+	}
+
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,228 @@
+package uaa_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuth clients", func() {
+	var (
+		server   *httptest.Server
+		handler  http.HandlerFunc
+		subject  uaa.Client
+		requests []*http.Request
+		bodies   []string
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = nil
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		}
+	})
+
+	JustBeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("bearer admin-token"))
+			body, err := ioutil.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+
+			handler(w, r)
+		}))
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("CreateOAuthClient", func() {
+		It("posts the client to /oauth/clients", func() {
+			_, err := subject.CreateOAuthClient("admin-token", uaa.OAuthClient{
+				ClientID:             "dashboard",
+				ClientSecret:         "s3cr3t",
+				Name:                 "Dashboard",
+				Scope:                []string{"openid", "cloud_controller.read"},
+				AuthorizedGrantTypes: []string{"authorization_code", "refresh_token"},
+				RedirectURI:          []string{"https://dashboard.example.com/**"},
+				AutoApprove:          uaa.AutoApprove{"openid"},
+				AccessTokenValidity:  600,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests[0].Method).To(Equal("POST"))
+			Expect(requests[0].URL.Path).To(Equal("/oauth/clients"))
+			Expect(bodies[0]).To(MatchJSON(`{
+				"client_id": "dashboard",
+				"client_secret": "s3cr3t",
+				"name": "Dashboard",
+				"scope": ["openid", "cloud_controller.read"],
+				"authorized_grant_types": ["authorization_code", "refresh_token"],
+				"redirect_uri": ["https://dashboard.example.com/**"],
+				"autoapprove": ["openid"],
+				"access_token_validity": 600
+			}`))
+		})
+
+		Context("when the client already exists", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusConflict)
+					w.Write([]byte(`{"error":"invalid_client","error_description":"Client already exists: dashboard"}`))
+				}
+			})
+
+			It("returns a conflict error", func() {
+				_, err := subject.CreateOAuthClient("admin-token", uaa.OAuthClient{ClientID: "dashboard"})
+				Expect(uaa.IsConflict(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("GetOAuthClient", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"client_id":"dashboard","scope":["openid"],"autoapprove":true,"lastModified":1500000000000}`))
+			}
+		})
+
+		It("gets the client", func() {
+			client, err := subject.GetOAuthClient("admin-token", "dashboard")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.ClientID).To(Equal("dashboard"))
+			Expect(client.Scope).To(Equal([]string{"openid"}))
+			Expect(client.AutoApprove).To(Equal(uaa.AutoApprove{"true"}))
+			Expect(client.LastModified).To(BeEquivalentTo(1500000000000))
+			Expect(requests[0].URL.Path).To(Equal("/oauth/clients/dashboard"))
+		})
+	})
+
+	Describe("ListAllOAuthClients", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("startIndex") == "1" {
+					w.Write([]byte(`{"resources":[{"client_id":"one"}],"startIndex":1,"totalResults":2}`))
+					return
+				}
+				w.Write([]byte(`{"resources":[{"client_id":"two"}],"startIndex":2,"totalResults":2}`))
+			}
+		})
+
+		It("pages through the clients", func() {
+			clients, err := subject.ListAllOAuthClients("admin-token", uaa.ScimQuery{Count: 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clients).To(Equal([]uaa.OAuthClient{{ClientID: "one"}, {ClientID: "two"}}))
+		})
+	})
+
+	Describe("UpdateOAuthClient", func() {
+		It("puts the client without its secret", func() {
+			_, err := subject.UpdateOAuthClient("admin-token", uaa.OAuthClient{ClientID: "dashboard", ClientSecret: "ignored", Name: "New name"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests[0].Method).To(Equal("PUT"))
+			Expect(requests[0].URL.Path).To(Equal("/oauth/clients/dashboard"))
+			Expect(bodies[0]).To(MatchJSON(`{"client_id":"dashboard","name":"New name"}`))
+		})
+	})
+
+	Describe("DeleteOAuthClient", func() {
+		It("deletes the client", func() {
+			Expect(subject.DeleteOAuthClient("admin-token", "dashboard")).To(Succeed())
+			Expect(requests[0].Method).To(Equal("DELETE"))
+			Expect(requests[0].URL.Path).To(Equal("/oauth/clients/dashboard"))
+		})
+	})
+
+	Describe("ChangeOAuthClientSecret", func() {
+		It("puts the new secret", func() {
+			Expect(subject.ChangeOAuthClientSecret("admin-token", "dashboard", "old", "new")).To(Succeed())
+			Expect(requests[0].Method).To(Equal("PUT"))
+			Expect(requests[0].URL.Path).To(Equal("/oauth/clients/dashboard/secret"))
+			Expect(bodies[0]).To(MatchJSON(`{"clientId":"dashboard","oldSecret":"old","secret":"new"}`))
+		})
+	})
+
+	Describe("EnsureOAuthClients", func() {
+		var existing map[string]string
+
+		BeforeEach(func() {
+			existing = map[string]string{
+				"unchanged": `{"client_id":"unchanged","scope":["b","a"],"authorities":["uaa.none"],"resource_ids":["none"],"access_token_validity":43200}`,
+				"outdated":  `{"client_id":"outdated","name":"Outdated","scope":["a"],"authorities":["clients.read"],"access_token_validity":600}`,
+			}
+
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "GET" {
+					w.Write([]byte(`{}`))
+					return
+				}
+
+				client, ok := existing[strings.TrimPrefix(r.URL.Path, "/oauth/clients/")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"error":"not_found","error_description":"No client with requested id"}`))
+					return
+				}
+				w.Write([]byte(client))
+			}
+		})
+
+		It("creates, updates or leaves the clients alone", func() {
+			changes, err := subject.EnsureOAuthClients("admin-token",
+				uaa.OAuthClient{ClientID: "unchanged", Scope: []string{"a", "b"}},
+				uaa.OAuthClient{ClientID: "outdated", Scope: []string{"a", "c"}},
+				uaa.OAuthClient{ClientID: "missing", ClientSecret: "s3cr3t"},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]uaa.ClientChange{
+				{ClientID: "unchanged", Action: uaa.ClientUnchanged},
+				{ClientID: "outdated", Action: uaa.ClientUpdated},
+				{ClientID: "missing", Action: uaa.ClientCreated},
+			}))
+
+			var writes []string
+			for i, request := range requests {
+				if request.Method != "GET" {
+					writes = append(writes, request.Method+" "+request.URL.Path)
+				}
+				if request.Method == "POST" {
+					var created map[string]interface{}
+					Expect(json.Unmarshal([]byte(bodies[i]), &created)).To(Succeed())
+					Expect(created["client_secret"]).To(Equal("s3cr3t"))
+				}
+				if request.Method == "PUT" {
+					Expect(bodies[i]).To(MatchJSON(`{"client_id":"outdated","name":"Outdated","scope":["a","c"],"authorities":["clients.read"],"access_token_validity":600}`))
+				}
+			}
+			Expect(writes).To(Equal([]string{"PUT /oauth/clients/outdated", "POST /oauth/clients"}))
+		})
+
+		Context("when uaa fails", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"error":"insufficient_scope","error_description":"Insufficient scope"}`))
+				}
+			})
+
+			It("stops and returns the error", func() {
+				changes, err := subject.EnsureOAuthClients("admin-token", uaa.OAuthClient{ClientID: "one"}, uaa.OAuthClient{ClientID: "two"})
+				Expect(err).To(MatchError("UAA Error: Insufficient scope (insufficient_scope)"))
+				Expect(changes).To(BeEmpty())
+				Expect(requests).To(HaveLen(1))
+			})
+		})
+	})
+})
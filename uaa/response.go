@@ -4,6 +4,8 @@ type authenticationResponse struct {
 	AccessToken      string `json:"access_token"`
 	TokenType        string `json:"token_type"`
 	RefreshToken     string `json:"refresh_token"`
+	IDToken          string `json:"id_token"`
+	ExpiresIn        int    `json:"expires_in"`
 	ErrorCode        string `json:"error"`
 	ErrorDescription string `json:"error_description"`
 }
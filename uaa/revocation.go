@@ -0,0 +1,73 @@
+package uaa
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+type Revoker interface {
+	RevokeToken(accessToken, tokenID string) error
+}
+
+type ContextRevoker interface {
+	RevokeTokenWithContext(ctx context.Context, accessToken, tokenID string) error
+}
+
+func (c *Client) RevokeToken(accessToken, tokenID string) error {
+	return c.RevokeTokenWithContext(context.Background(), accessToken, tokenID)
+}
+
+func (c *Client) RevokeTokenWithContext(ctx context.Context, accessToken, tokenID string) error {
+	if tokenID == "" {
+		return errors.New("Missing token id")
+	}
+
+	return c.revoke(ctx, "DELETE", "/oauth/token/revoke/"+url.PathEscape(tokenID), accessToken)
+}
+
+func (c *Client) RevokeUserTokens(accessToken, userID string) error {
+	return c.RevokeUserTokensWithContext(context.Background(), accessToken, userID)
+}
+
+func (c *Client) RevokeUserTokensWithContext(ctx context.Context, accessToken, userID string) error {
+	if userID == "" {
+		return errors.New("Missing user id")
+	}
+
+	return c.revoke(ctx, "GET", "/oauth/token/revoke/user/"+url.PathEscape(userID), accessToken)
+}
+
+func (c *Client) RevokeClientTokens(accessToken, clientID string) error {
+	return c.RevokeClientTokensWithContext(context.Background(), accessToken, clientID)
+}
+
+func (c *Client) RevokeClientTokensWithContext(ctx context.Context, accessToken, clientID string) error {
+	if clientID == "" {
+		return errors.New("Missing client id")
+	}
+
+	return c.revoke(ctx, "GET", "/oauth/token/revoke/client/"+url.PathEscape(clientID), accessToken)
+}
+
+func (c *Client) revoke(ctx context.Context, method, path, accessToken string) error {
+	request, err := c.newRequest(ctx, method, path, nil)
+	if err != nil {
+		return err
+	}
+	setBearerAuth(request, accessToken)
+
+	_, err = c.runRequest(request)
+	return err
+}
+
+// TokenID returns the id UAA uses to revoke the given token: the jti claim
+// for JWT tokens, or the token itself for opaque tokens.
+func TokenID(token string) string {
+	claims, err := ParseClaims(token)
+	if err != nil || claims.JTI == "" {
+		return stripTokenType(token)
+	}
+
+	return claims.JTI
+}
@@ -0,0 +1,102 @@
+package uaa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Revocation", func() {
+	var (
+		server        *httptest.Server
+		subject       uaa.Client
+		requestMethod string
+		requestPath   string
+		authorization string
+		statusCode    int
+	)
+
+	BeforeEach(func() {
+		statusCode = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestMethod = r.Method
+			requestPath = r.URL.Path
+			authorization = r.Header.Get("Authorization")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(statusCode)
+			if statusCode == http.StatusOK {
+				w.Write([]byte(`{"status":"ok"}`))
+				return
+			}
+			w.Write([]byte(`{"error":"access_denied","error_description":"Access is denied"}`))
+		}))
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("RevokeToken", func() {
+		It("revokes the token by id", func() {
+			err := subject.RevokeToken("my-access-token", "some-token-id")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requestMethod).To(Equal("DELETE"))
+			Expect(requestPath).To(Equal("/oauth/token/revoke/some-token-id"))
+			Expect(authorization).To(Equal("bearer my-access-token"))
+		})
+
+		It("requires a token id", func() {
+			err := subject.RevokeToken("my-access-token", "")
+			Expect(err).To(MatchError("Missing token id"))
+		})
+
+		Context("when UAA denies the revocation", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusForbidden
+			})
+
+			It("returns the uaa error", func() {
+				err := subject.RevokeToken("my-access-token", "some-token-id")
+				Expect(err).To(MatchError("UAA Error: Access is denied (access_denied)"))
+			})
+		})
+	})
+
+	Describe("RevokeUserTokens", func() {
+		It("revokes all the user tokens", func() {
+			err := subject.RevokeUserTokens("bearer admin-token", "some-user-id")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requestMethod).To(Equal("GET"))
+			Expect(requestPath).To(Equal("/oauth/token/revoke/user/some-user-id"))
+			Expect(authorization).To(Equal("bearer admin-token"))
+		})
+	})
+
+	Describe("RevokeClientTokens", func() {
+		It("revokes all the client tokens", func() {
+			err := subject.RevokeClientTokens("admin-token", "some-client")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requestMethod).To(Equal("GET"))
+			Expect(requestPath).To(Equal("/oauth/token/revoke/client/some-client"))
+		})
+	})
+
+	Describe("TokenID", func() {
+		It("uses the jti of JWT tokens", func() {
+			Expect(uaa.TokenID(encodeToken(`{"jti":"some-jti-r"}`))).To(Equal("some-jti-r"))
+		})
+
+		It("uses the value of opaque tokens", func() {
+			Expect(uaa.TokenID("opaque-refresh-token-r")).To(Equal("opaque-refresh-token-r"))
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package uaa
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+type TokenKey struct {
+	Kid   string `json:"kid"`
+	Kty   string `json:"kty"`
+	Alg   string `json:"alg"`
+	Use   string `json:"use"`
+	N     string `json:"n"`
+	E     string `json:"e"`
+	Value string `json:"value"`
+}
+
+type tokenKeysResponse struct {
+	Keys []TokenKey `json:"keys"`
+}
+
+func (c *Client) TokenKeys() ([]TokenKey, error) {
+	return c.TokenKeysWithContext(context.Background())
+}
+
+// TokenKeysWithContext only fetches the keys over https with certificate
+// verification, using a verifying transport in place of the default one.
+func (c *Client) TokenKeysWithContext(ctx context.Context) ([]TokenKey, error) {
+	if !strings.HasPrefix(c.endpoint, "https://") {
+		return nil, errors.New("Token keys must be fetched over https")
+	}
+
+	keysClient := *c
+	if keysClient.httpClient == defaultHTTPClient {
+		keysClient.httpClient = tokenKeysHTTPClient
+	}
+	if skipsVerification(keysClient.httpClient) {
+		return nil, errors.New("Token keys can't be fetched without certificate verification")
+	}
+
+	request, err := keysClient.newRequest(ctx, "GET", "/token_keys", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keysResp := new(tokenKeysResponse)
+	err = keysClient.runJSONRequest(request, keysResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return keysResp.Keys, nil
+}
+
+func skipsVerification(httpClient *http.Client) bool {
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil {
+		return false
+	}
+
+	return transport.TLSClientConfig.InsecureSkipVerify
+}
+
+func (k TokenKey) PublicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "" && k.Kty != "RSA" {
+		return nil, fmt.Errorf("Unsupported key type: %s", k.Kty)
+	}
+
+	if k.N != "" && k.E != "" {
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid key modulus: %s", err.Error())
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid key exponent: %s", err.Error())
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	}
+
+	block, _ := pem.Decode([]byte(k.Value))
+	if block == nil {
+		return nil, errors.New("Invalid key: no modulus, exponent or PEM value")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid key value: %s", err.Error())
+	}
+
+	publicKey, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("Invalid key value: not an RSA public key")
+	}
+
+	return publicKey, nil
+}
@@ -0,0 +1,100 @@
+package uaa
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+func (t Tokens) OAuth2Token() *oauth2.Token {
+	token := &oauth2.Token{
+		AccessToken:  stripTokenType(t.AccessToken),
+		TokenType:    t.TokenType,
+		RefreshToken: t.RefreshToken,
+		Expiry:       t.expiry(),
+	}
+	if t.IDToken != "" {
+		token = token.WithExtra(map[string]interface{}{"id_token": t.IDToken})
+	}
+
+	return token
+}
+
+func TokensFromOAuth2(token *oauth2.Token) Tokens {
+	tokens := Tokens{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		TokenType:    token.TokenType,
+		Expiry:       token.Expiry,
+	}
+	if idToken, ok := token.Extra("id_token").(string); ok {
+		tokens.IDToken = idToken
+	}
+
+	return tokens
+}
+
+func (c *Client) TokenSource(ctx context.Context, tokens Tokens) oauth2.TokenSource {
+	source := &refreshTokenSource{
+		ctx:          ctx,
+		client:       c,
+		refreshToken: tokens.RefreshToken,
+	}
+
+	return oauth2.ReuseTokenSource(tokens.OAuth2Token(), source)
+}
+
+func (c *Client) ClientCredentialsTokenSource(ctx context.Context, scopes ...string) oauth2.TokenSource {
+	source := &clientCredentialsTokenSource{
+		ctx:    ctx,
+		client: c,
+		scopes: scopes,
+	}
+
+	return oauth2.ReuseTokenSource(nil, source)
+}
+
+type refreshTokenSource struct {
+	ctx          context.Context
+	client       *Client
+	mutex        sync.Mutex
+	refreshToken string
+}
+
+func (s *refreshTokenSource) Token() (*oauth2.Token, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.refreshToken == "" {
+		return nil, errors.New("Token expired and no refresh token is available")
+	}
+
+	tokens, err := s.client.RefreshTokenWithContext(s.ctx, s.refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokens.RefreshToken == "" {
+		tokens.RefreshToken = s.refreshToken
+	}
+	s.refreshToken = tokens.RefreshToken
+
+	return tokens.OAuth2Token(), nil
+}
+
+type clientCredentialsTokenSource struct {
+	ctx    context.Context
+	client *Client
+	scopes []string
+}
+
+func (s *clientCredentialsTokenSource) Token() (*oauth2.Token, error) {
+	tokens, err := s.client.AuthenticateWithClientCredentialsWithContext(s.ctx, s.scopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens.OAuth2Token(), nil
+}
@@ -0,0 +1,132 @@
+package uaa_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TokenSource", func() {
+	var (
+		server    *httptest.Server
+		subject   uaa.Client
+		requests  []*http.Request
+		expiresIn int
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		expiresIn = 3600
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).To(Succeed())
+			requests = append(requests, r)
+
+			fmt.Fprintf(w, `{"access_token":"access-%d","refresh_token":"refresh-%d","token_type":"bearer","expires_in":%d}`,
+				len(requests), len(requests), expiresIn)
+		}))
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("TokenSource", func() {
+		It("reuses the current tokens until they expire", func() {
+			source := subject.TokenSource(context.Background(), uaa.Tokens{
+				AccessToken:  "current-access",
+				RefreshToken: "current-refresh",
+				Expiry:       time.Now().Add(time.Hour),
+			})
+
+			token, err := source.Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("current-access"))
+			Expect(requests).To(BeEmpty())
+		})
+
+		It("refreshes expired tokens and keeps the rotated refresh token", func() {
+			expiresIn = 1
+			source := subject.TokenSource(context.Background(), uaa.Tokens{
+				AccessToken:  "current-access",
+				RefreshToken: "current-refresh",
+				Expiry:       time.Now().Add(-time.Minute),
+			})
+
+			token, err := source.Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("access-1"))
+			Expect(token.RefreshToken).To(Equal("refresh-1"))
+
+			token, err = source.Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("access-2"))
+
+			Expect(requests).To(HaveLen(2))
+			Expect(requests[0].PostForm.Get("grant_type")).To(Equal("refresh_token"))
+			Expect(requests[0].PostForm.Get("refresh_token")).To(Equal("current-refresh"))
+			Expect(requests[1].PostForm.Get("refresh_token")).To(Equal("refresh-1"))
+		})
+
+		It("fails when there is no refresh token", func() {
+			source := subject.TokenSource(context.Background(), uaa.Tokens{})
+
+			_, err := source.Token()
+			Expect(err).To(MatchError("Token expired and no refresh token is available"))
+		})
+	})
+
+	Describe("ClientCredentialsTokenSource", func() {
+		It("fetches a token once and reuses it", func() {
+			subject.ClientID = "admin"
+			subject.ClientSecret = "admin-secret"
+			source := subject.ClientCredentialsTokenSource(context.Background(), "scim.read", "scim.write")
+
+			for i := 0; i < 3; i++ {
+				token, err := source.Token()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(token.AccessToken).To(Equal("access-1"))
+				Expect(token.Expiry).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+			}
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].PostForm.Get("grant_type")).To(Equal("client_credentials"))
+			Expect(requests[0].PostForm.Get("scope")).To(Equal("scim.read scim.write"))
+			username, _, _ := requests[0].BasicAuth()
+			Expect(username).To(Equal("admin"))
+		})
+	})
+
+	Describe("conversions", func() {
+		It("round trips tokens through oauth2 tokens", func() {
+			tokens := uaa.Tokens{
+				AccessToken:  "access",
+				RefreshToken: "refresh",
+				TokenType:    "bearer",
+				IDToken:      "id",
+				Expiry:       time.Now().Add(time.Hour),
+			}
+
+			Expect(uaa.TokensFromOAuth2(tokens.OAuth2Token())).To(Equal(tokens))
+		})
+
+		It("uses the access token expiry when none is known", func() {
+			expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+			tokens := uaa.Tokens{AccessToken: encodeToken(fmt.Sprintf(`{"exp":%d}`, expiry.Unix()))}
+
+			Expect(tokens.OAuth2Token().Expiry).To(BeTemporally("==", expiry))
+		})
+
+		It("drops the token type prefix from the access token", func() {
+			token := uaa.Tokens{AccessToken: "bearer abc"}.OAuth2Token()
+			Expect(token.AccessToken).To(Equal("abc"))
+		})
+	})
+})
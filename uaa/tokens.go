@@ -1,7 +1,32 @@
 package uaa
 
+import "time"
+
 type Tokens struct {
 	AccessToken  string
 	RefreshToken string
 	TokenType    string
+	IDToken      string
+	Expiry       time.Time
+}
+
+func (t Tokens) AccessTokenClaims() (*Claims, error) {
+	return ParseClaims(t.AccessToken)
+}
+
+func (t Tokens) IDTokenClaims() (*Claims, error) {
+	return ParseClaims(t.IDToken)
+}
+
+func (t Tokens) expiry() time.Time {
+	if !t.Expiry.IsZero() {
+		return t.Expiry
+	}
+
+	claims, err := t.AccessTokenClaims()
+	if err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return claims.ExpiresAt()
 }
@@ -0,0 +1,59 @@
+package uaa
+
+import (
+	"context"
+	"time"
+)
+
+type UserInfo struct {
+	UserID            string `json:"user_id"`
+	Sub               string `json:"sub"`
+	UserName          string `json:"user_name"`
+	Name              string `json:"name"`
+	GivenName         string `json:"given_name"`
+	FamilyName        string `json:"family_name"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PhoneNumber       string `json:"phone_number"`
+	PreviousLogonTime int64  `json:"previous_logon_time"`
+}
+
+type UserInfoFetcher interface {
+	UserInfoWithContext(ctx context.Context, accessToken string) (*UserInfo, error)
+}
+
+func (c *Client) UserInfo(accessToken string) (*UserInfo, error) {
+	return c.UserInfoWithContext(context.Background(), accessToken)
+}
+
+func (c *Client) UserInfoWithContext(ctx context.Context, accessToken string) (*UserInfo, error) {
+	request, err := c.newRequest(ctx, "GET", "/userinfo", nil)
+	if err != nil {
+		return nil, err
+	}
+	setBearerAuth(request, accessToken)
+
+	info := new(UserInfo)
+	err = c.runJSONRequest(request, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (i *UserInfo) ID() string {
+	if i.UserID != "" {
+		return i.UserID
+	}
+
+	return i.Sub
+}
+
+func (i *UserInfo) PreviousLogon() time.Time {
+	if i.PreviousLogonTime == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, i.PreviousLogonTime*int64(time.Millisecond))
+}
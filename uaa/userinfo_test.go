@@ -0,0 +1,83 @@
+package uaa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UserInfo", func() {
+	var (
+		server  *httptest.Server
+		handler http.HandlerFunc
+		subject uaa.Client
+	)
+
+	BeforeEach(func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal("GET"))
+			Expect(r.URL.Path).To(Equal("/userinfo"))
+			Expect(r.Header.Get("Authorization")).To(Equal("bearer my-token"))
+
+			w.Write([]byte(`{
+				"user_id": "user-guid",
+				"sub": "user-guid",
+				"user_name": "marissa",
+				"given_name": "Marissa",
+				"family_name": "Bloggs",
+				"name": "Marissa Bloggs",
+				"email": "marissa@example.com",
+				"email_verified": true,
+				"phone_number": "555-1234",
+				"previous_logon_time": 1500000000123
+			}`))
+		}
+	})
+
+	JustBeforeEach(func() {
+		server = httptest.NewServer(handler)
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns the user info for the token", func() {
+		info, err := subject.UserInfo("bearer my-token")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(info.ID()).To(Equal("user-guid"))
+		Expect(info.UserName).To(Equal("marissa"))
+		Expect(info.Name).To(Equal("Marissa Bloggs"))
+		Expect(info.GivenName).To(Equal("Marissa"))
+		Expect(info.FamilyName).To(Equal("Bloggs"))
+		Expect(info.Email).To(Equal("marissa@example.com"))
+		Expect(info.EmailVerified).To(BeTrue())
+		Expect(info.PhoneNumber).To(Equal("555-1234"))
+		Expect(info.PreviousLogon()).To(Equal(time.Unix(1500000000, 123000000)))
+	})
+
+	Context("when the token is rejected", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"invalid_token","error_description":"Invalid access token"}`))
+			}
+		})
+
+		It("returns a uaa error", func() {
+			_, err := subject.UserInfo("my-token")
+			Expect(err).To(MatchError("UAA Error: Invalid access token (invalid_token)"))
+
+			uaaErr, ok := err.(*uaa.Error)
+			Expect(ok).To(BeTrue())
+			Expect(uaaErr.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+	})
+})
@@ -0,0 +1,302 @@
+package uaa
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type User struct {
+	ID           string        `json:"id,omitempty"`
+	ExternalID   string        `json:"externalId,omitempty"`
+	UserName     string        `json:"userName,omitempty"`
+	Name         *UserFullName `json:"name,omitempty"`
+	Emails       []UserEmail   `json:"emails,omitempty"`
+	PhoneNumbers []UserPhone   `json:"phoneNumbers,omitempty"`
+	Groups       []UserGroup   `json:"groups,omitempty"`
+	Active       *bool         `json:"active,omitempty"`
+	Verified     *bool         `json:"verified,omitempty"`
+	Origin       string        `json:"origin,omitempty"`
+	ZoneID       string        `json:"zoneId,omitempty"`
+	Password     string        `json:"password,omitempty"`
+	Meta         *Meta         `json:"meta,omitempty"`
+	Schemas      []string      `json:"schemas,omitempty"`
+}
+
+type UserFullName struct {
+	FamilyName string `json:"familyName,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+}
+
+type UserEmail struct {
+	Value   string `json:"value"`
+	Primary bool   `json:"primary"`
+}
+
+type UserPhone struct {
+	Value string `json:"value"`
+}
+
+type UserGroup struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+type Meta struct {
+	Version      int    `json:"version"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+type UserID struct {
+	ID       string `json:"id"`
+	UserName string `json:"userName"`
+	Origin   string `json:"origin"`
+}
+
+type ScimQuery struct {
+	Filter     string
+	Attributes []string
+	SortBy     string
+	SortOrder  string
+	StartIndex int
+	Count      int
+}
+
+type UsersPage struct {
+	Resources    []User `json:"resources"`
+	StartIndex   int    `json:"startIndex"`
+	ItemsPerPage int    `json:"itemsPerPage"`
+	TotalResults int    `json:"totalResults"`
+}
+
+func (c *Client) CreateUser(accessToken string, user User) (*User, error) {
+	return c.CreateUserWithContext(context.Background(), accessToken, user)
+}
+
+func (c *Client) CreateUserWithContext(ctx context.Context, accessToken string, user User) (*User, error) {
+	return c.sendUser(ctx, "POST", "/Users", accessToken, user, "")
+}
+
+func (c *Client) GetUser(accessToken, userID string) (*User, error) {
+	return c.GetUserWithContext(context.Background(), accessToken, userID)
+}
+
+func (c *Client) GetUserWithContext(ctx context.Context, accessToken, userID string) (*User, error) {
+	if userID == "" {
+		return nil, errors.New("Missing user id")
+	}
+
+	user := new(User)
+	err := c.scimRequest(ctx, "GET", "/Users/"+url.PathEscape(userID), accessToken, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (c *Client) FindUsers(accessToken string, query ScimQuery) (*UsersPage, error) {
+	return c.FindUsersWithContext(context.Background(), accessToken, query)
+}
+
+func (c *Client) FindUsersWithContext(ctx context.Context, accessToken string, query ScimQuery) (*UsersPage, error) {
+	page := new(UsersPage)
+	err := c.scimRequest(ctx, "GET", "/Users"+query.encode(), accessToken, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (c *Client) FindAllUsers(accessToken string, query ScimQuery) ([]User, error) {
+	return c.FindAllUsersWithContext(context.Background(), accessToken, query)
+}
+
+func (c *Client) FindAllUsersWithContext(ctx context.Context, accessToken string, query ScimQuery) ([]User, error) {
+	var users []User
+	err := findAll(query, func(query ScimQuery) (int, int, error) {
+		page, err := c.FindUsersWithContext(ctx, accessToken, query)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		users = append(users, page.Resources...)
+		return len(page.Resources), page.TotalResults, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (c *Client) UpdateUser(accessToken string, user User) (*User, error) {
+	return c.UpdateUserWithContext(context.Background(), accessToken, user)
+}
+
+func (c *Client) UpdateUserWithContext(ctx context.Context, accessToken string, user User) (*User, error) {
+	if user.ID == "" {
+		return nil, errors.New("Missing user id")
+	}
+
+	if user.Meta == nil {
+		return nil, errors.New("Missing user version")
+	}
+
+	return c.sendUser(ctx, "PUT", "/Users/"+url.PathEscape(user.ID), accessToken, user, ifMatch(user.Meta))
+}
+
+func (c *Client) DeleteUser(accessToken, userID string) error {
+	return c.DeleteUserWithContext(context.Background(), accessToken, userID)
+}
+
+func (c *Client) DeleteUserWithContext(ctx context.Context, accessToken, userID string) error {
+	if userID == "" {
+		return errors.New("Missing user id")
+	}
+
+	return c.scimRequest(ctx, "DELETE", "/Users/"+url.PathEscape(userID), accessToken, nil)
+}
+
+func (c *Client) ChangeUserPassword(accessToken, userID, oldPassword, newPassword string) error {
+	return c.ChangeUserPasswordWithContext(context.Background(), accessToken, userID, oldPassword, newPassword)
+}
+
+func (c *Client) ChangeUserPasswordWithContext(ctx context.Context, accessToken, userID, oldPassword, newPassword string) error {
+	if userID == "" {
+		return errors.New("Missing user id")
+	}
+
+	body := map[string]string{"password": newPassword}
+	if oldPassword != "" {
+		body["oldPassword"] = oldPassword
+	}
+
+	return c.sendScimJSON(ctx, "PUT", "/Users/"+url.PathEscape(userID)+"/password", accessToken, body, "", nil)
+}
+
+func (c *Client) LookupUserIDs(accessToken string, userNames ...string) ([]UserID, error) {
+	return c.LookupUserIDsWithContext(context.Background(), accessToken, userNames...)
+}
+
+func (c *Client) LookupUserIDsWithContext(ctx context.Context, accessToken string, userNames ...string) ([]UserID, error) {
+	if len(userNames) == 0 {
+		return nil, nil
+	}
+
+	filters := make([]string, len(userNames))
+	for i, userName := range userNames {
+		filters[i] = ScimEqual("userName", userName)
+	}
+
+	query := url.Values{
+		"filter":          {strings.Join(filters, " or ")},
+		"includeInactive": {"true"},
+	}
+
+	var response struct {
+		Resources []UserID `json:"resources"`
+	}
+	err := c.scimRequest(ctx, "GET", "/ids/Users?"+query.Encode(), accessToken, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Resources, nil
+}
+
+func ScimEqual(attribute, value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `"`, `\"`, -1)
+	return attribute + ` eq "` + value + `"`
+}
+
+func (c *Client) sendUser(ctx context.Context, method, path, accessToken string, user User, version string) (*User, error) {
+	result := new(User)
+	err := c.sendScimJSON(ctx, method, path, accessToken, user, version, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *Client) sendScimJSON(ctx context.Context, method, path, accessToken string, body interface{}, version string, response interface{}) error {
+	request, err := c.newJSONRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	setBearerAuth(request, accessToken)
+	if version != "" {
+		request.Header.Set("If-Match", version)
+	}
+
+	return c.runJSONRequest(request, response)
+}
+
+func (c *Client) scimRequest(ctx context.Context, method, path, accessToken string, response interface{}) error {
+	request, err := c.newRequest(ctx, method, path, nil)
+	if err != nil {
+		return err
+	}
+	setBearerAuth(request, accessToken)
+
+	return c.runJSONRequest(request, response)
+}
+
+func findAll(query ScimQuery, fetch func(query ScimQuery) (int, int, error)) error {
+	if query.StartIndex < 1 {
+		query.StartIndex = 1
+	}
+
+	fetched := 0
+	for {
+		count, total, err := fetch(query)
+		if err != nil {
+			return err
+		}
+
+		fetched += count
+		if count == 0 || fetched >= total {
+			return nil
+		}
+
+		query.StartIndex += count
+	}
+}
+
+func ifMatch(meta *Meta) string {
+	return strconv.Itoa(meta.Version)
+}
+
+func (q ScimQuery) encode() string {
+	values := url.Values{}
+	if q.Filter != "" {
+		values.Set("filter", q.Filter)
+	}
+	if len(q.Attributes) > 0 {
+		values.Set("attributes", strings.Join(q.Attributes, ","))
+	}
+	if q.SortBy != "" {
+		values.Set("sortBy", q.SortBy)
+	}
+	if q.SortOrder != "" {
+		values.Set("sortOrder", q.SortOrder)
+	}
+	if q.StartIndex > 0 {
+		values.Set("startIndex", strconv.Itoa(q.StartIndex))
+	}
+	if q.Count > 0 {
+		values.Set("count", strconv.Itoa(q.Count))
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
@@ -0,0 +1,259 @@
+package uaa_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Users", func() {
+	var (
+		server   *httptest.Server
+		handler  http.HandlerFunc
+		subject  uaa.Client
+		requests []*http.Request
+		bodies   []string
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = nil
+	})
+
+	JustBeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("bearer admin-token"))
+			body, err := ioutil.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+
+			handler(w, r)
+		}))
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("CreateUser", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":"user-guid","userName":"marissa","active":true,"meta":{"version":0,"created":"2016-01-01T00:00:00.000Z"}}`))
+			}
+		})
+
+		It("posts the user to /Users", func() {
+			active := true
+			user, err := subject.CreateUser("admin-token", uaa.User{
+				UserName: "marissa",
+				Name:     &uaa.UserFullName{GivenName: "Marissa", FamilyName: "Bloggs"},
+				Emails:   []uaa.UserEmail{{Value: "marissa@example.com", Primary: true}},
+				Active:   &active,
+				Password: "koala",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.ID).To(Equal("user-guid"))
+			Expect(*user.Active).To(BeTrue())
+			Expect(user.Meta.Version).To(Equal(0))
+
+			Expect(requests[0].Method).To(Equal("POST"))
+			Expect(requests[0].URL.Path).To(Equal("/Users"))
+			Expect(requests[0].Header.Get("Content-Type")).To(Equal("application/json"))
+			Expect(bodies[0]).To(MatchJSON(`{
+				"userName": "marissa",
+				"name": {"givenName": "Marissa", "familyName": "Bloggs"},
+				"emails": [{"value": "marissa@example.com", "primary": true}],
+				"active": true,
+				"password": "koala"
+			}`))
+		})
+
+		Context("when the user already exists", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusConflict)
+					w.Write([]byte(`{"error":"scim_resource_already_exists","error_description":"Username already in use: marissa"}`))
+				}
+			})
+
+			It("returns the uaa error", func() {
+				_, err := subject.CreateUser("admin-token", uaa.User{UserName: "marissa"})
+				Expect(err).To(MatchError("UAA Error: Username already in use: marissa (scim_resource_already_exists)"))
+				Expect(err.(*uaa.Error).StatusCode).To(Equal(http.StatusConflict))
+			})
+		})
+	})
+
+	Describe("GetUser", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"id":"user-guid","userName":"marissa","groups":[{"value":"group-guid","display":"openid","type":"DIRECT"}],"meta":{"version":3}}`))
+			}
+		})
+
+		It("gets the user by id", func() {
+			user, err := subject.GetUser("admin-token", "user-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.UserName).To(Equal("marissa"))
+			Expect(user.Groups).To(Equal([]uaa.UserGroup{{Value: "group-guid", Display: "openid", Type: "DIRECT"}}))
+			Expect(user.Meta.Version).To(Equal(3))
+
+			Expect(requests[0].Method).To(Equal("GET"))
+			Expect(requests[0].URL.Path).To(Equal("/Users/user-guid"))
+		})
+
+		It("requires an id", func() {
+			_, err := subject.GetUser("admin-token", "")
+			Expect(err).To(MatchError("Missing user id"))
+		})
+	})
+
+	Describe("FindUsers", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				startIndex, _ := strconv.Atoi(r.URL.Query().Get("startIndex"))
+				fmt.Fprintf(w, `{"resources":[{"id":"user-%d"}],"startIndex":%d,"itemsPerPage":1,"totalResults":3}`, startIndex, startIndex)
+			}
+		})
+
+		It("sends the query parameters", func() {
+			page, err := subject.FindUsers("admin-token", uaa.ScimQuery{
+				Filter:     uaa.ScimEqual("origin", "ldap"),
+				Attributes: []string{"id", "userName"},
+				SortBy:     "userName",
+				SortOrder:  "descending",
+				StartIndex: 2,
+				Count:      1,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(page.TotalResults).To(Equal(3))
+			Expect(page.Resources).To(Equal([]uaa.User{{ID: "user-2"}}))
+
+			query := requests[0].URL.Query()
+			Expect(requests[0].URL.Path).To(Equal("/Users"))
+			Expect(query.Get("filter")).To(Equal(`origin eq "ldap"`))
+			Expect(query.Get("attributes")).To(Equal("id,userName"))
+			Expect(query.Get("sortBy")).To(Equal("userName"))
+			Expect(query.Get("sortOrder")).To(Equal("descending"))
+			Expect(query.Get("startIndex")).To(Equal("2"))
+			Expect(query.Get("count")).To(Equal("1"))
+		})
+
+		It("pages through all the results with FindAllUsers", func() {
+			users, err := subject.FindAllUsers("admin-token", uaa.ScimQuery{Count: 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(users).To(Equal([]uaa.User{{ID: "user-1"}, {ID: "user-2"}, {ID: "user-3"}}))
+			Expect(requests).To(HaveLen(3))
+		})
+	})
+
+	Describe("UpdateUser", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"id":"user-guid","userName":"marissa2","meta":{"version":4}}`))
+			}
+		})
+
+		It("puts the user using its version", func() {
+			user, err := subject.UpdateUser("admin-token", uaa.User{
+				ID:       "user-guid",
+				UserName: "marissa2",
+				Meta:     &uaa.Meta{Version: 3},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.Meta.Version).To(Equal(4))
+
+			Expect(requests[0].Method).To(Equal("PUT"))
+			Expect(requests[0].URL.Path).To(Equal("/Users/user-guid"))
+			Expect(requests[0].Header.Get("If-Match")).To(Equal("3"))
+
+			var body map[string]interface{}
+			Expect(json.Unmarshal([]byte(bodies[0]), &body)).To(Succeed())
+			Expect(body["userName"]).To(Equal("marissa2"))
+		})
+
+		It("requires a version", func() {
+			_, err := subject.UpdateUser("admin-token", uaa.User{ID: "user-guid"})
+			Expect(err).To(MatchError("Missing user version"))
+			Expect(requests).To(BeEmpty())
+		})
+
+		It("requires an id", func() {
+			_, err := subject.UpdateUser("admin-token", uaa.User{})
+			Expect(err).To(MatchError("Missing user id"))
+		})
+	})
+
+	Describe("DeleteUser", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"id":"user-guid"}`))
+			}
+		})
+
+		It("deletes the user", func() {
+			Expect(subject.DeleteUser("admin-token", "user-guid")).To(Succeed())
+			Expect(requests[0].Method).To(Equal("DELETE"))
+			Expect(requests[0].URL.Path).To(Equal("/Users/user-guid"))
+		})
+	})
+
+	Describe("ChangeUserPassword", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"status":"ok","message":"password updated"}`))
+			}
+		})
+
+		It("puts the new password", func() {
+			Expect(subject.ChangeUserPassword("admin-token", "user-guid", "old", "new")).To(Succeed())
+			Expect(requests[0].Method).To(Equal("PUT"))
+			Expect(requests[0].URL.Path).To(Equal("/Users/user-guid/password"))
+			Expect(bodies[0]).To(MatchJSON(`{"oldPassword":"old","password":"new"}`))
+		})
+
+		It("omits the old password when resetting as an admin", func() {
+			Expect(subject.ChangeUserPassword("admin-token", "user-guid", "", "new")).To(Succeed())
+			Expect(bodies[0]).To(MatchJSON(`{"password":"new"}`))
+		})
+	})
+
+	Describe("LookupUserIDs", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"resources":[{"id":"id-1","userName":"marissa","origin":"uaa"},{"id":"id-2","userName":"bob\"s","origin":"ldap"}]}`))
+			}
+		})
+
+		It("looks the ids up by username", func() {
+			ids, err := subject.LookupUserIDs("admin-token", "marissa", `bob"s`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids).To(Equal([]uaa.UserID{
+				{ID: "id-1", UserName: "marissa", Origin: "uaa"},
+				{ID: "id-2", UserName: `bob"s`, Origin: "ldap"},
+			}))
+
+			Expect(requests[0].URL.Path).To(Equal("/ids/Users"))
+			Expect(requests[0].URL.Query().Get("filter")).To(Equal(`userName eq "marissa" or userName eq "bob\"s"`))
+			Expect(requests[0].URL.Query().Get("includeInactive")).To(Equal("true"))
+		})
+
+		It("doesn't call uaa without usernames", func() {
+			ids, err := subject.LookupUserIDs("admin-token")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids).To(BeEmpty())
+			Expect(requests).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,243 @@
+package uaa
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("Invalid token signature")
+	ErrUnknownKey       = errors.New("Unknown token signing key")
+	ErrTokenExpired     = errors.New("Token has expired")
+	ErrTokenNotYetValid = errors.New("Token is not valid yet")
+	ErrInvalidIssuer    = errors.New("Invalid token issuer")
+	ErrInvalidAudience  = errors.New("Invalid token audience")
+	ErrKeysUnavailable  = errors.New("Failed to fetch token keys")
+)
+
+type KeysFetcher interface {
+	TokenKeysWithContext(ctx context.Context) ([]TokenKey, error)
+}
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type keysFetch struct {
+	done chan struct{}
+	err  error
+}
+
+type Verifier struct {
+	fetcher            KeysFetcher
+	issuer             string
+	audiences          []string
+	mutex              sync.Mutex
+	keys               map[string]*rsa.PublicKey
+	lastFetch          time.Time
+	fetch              *keysFetch
+	ClockSkew          time.Duration
+	MinRefreshInterval time.Duration
+	Now                func() time.Time
+}
+
+func NewVerifier(fetcher KeysFetcher, issuer string, audiences ...string) *Verifier {
+	return &Verifier{
+		fetcher:            fetcher,
+		issuer:             issuer,
+		audiences:          audiences,
+		keys:               map[string]*rsa.PublicKey{},
+		ClockSkew:          time.Minute,
+		MinRefreshInterval: 10 * time.Second,
+		Now:                time.Now,
+	}
+}
+
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	return v.VerifyWithContext(context.Background(), token)
+}
+
+func (v *Verifier) VerifyWithContext(ctx context.Context, token string) (*Claims, error) {
+	segments, err := splitToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	headerBytes, err := decodeSegment(segments[0])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid token header: %s", err.Error())
+	}
+
+	var header tokenHeader
+	err = json.Unmarshal(headerBytes, &header)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid token header: %s", err.Error())
+	}
+
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("Unsupported token algorithm: %s", header.Alg)
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := decodeSegment(segments[2])
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	digest := sha256.Sum256([]byte(segments[0] + "." + segments[1]))
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	claims, err := ParseClaims(token)
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.validateClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) validateClaims(claims *Claims) error {
+	now := v.Now()
+
+	if claims.Exp == 0 || now.Add(-v.ClockSkew).After(claims.ExpiresAt()) {
+		return ErrTokenExpired
+	}
+
+	if claims.Nbf != 0 && now.Add(v.ClockSkew).Before(time.Unix(claims.Nbf, 0)) {
+		return ErrTokenNotYetValid
+	}
+
+	if claims.Iat != 0 && now.Add(v.ClockSkew).Before(claims.IssuedAt()) {
+		return ErrTokenNotYetValid
+	}
+
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return ErrInvalidIssuer
+	}
+
+	if len(v.audiences) > 0 {
+		for _, audience := range v.audiences {
+			if claims.Audience.Contains(audience) {
+				return nil
+			}
+		}
+		return ErrInvalidAudience
+	}
+
+	return nil
+}
+
+func (v *Verifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mutex.Lock()
+	if key, ok := v.lookupKey(kid); ok {
+		v.mutex.Unlock()
+		return key, nil
+	}
+
+	fetch := v.fetch
+	if fetch == nil {
+		if !v.lastFetch.IsZero() && v.Now().Sub(v.lastFetch) < v.MinRefreshInterval {
+			v.mutex.Unlock()
+			return nil, ErrUnknownKey
+		}
+
+		fetch = &keysFetch{done: make(chan struct{})}
+		v.fetch = fetch
+		v.lastFetch = v.Now()
+		v.mutex.Unlock()
+
+		v.refreshKeys(ctx, fetch)
+	} else {
+		v.mutex.Unlock()
+	}
+
+	select {
+	case <-fetch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if fetch.err != nil {
+		return nil, fetch.err
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if key, ok := v.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func (v *Verifier) lookupKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// refreshKeys fetches the keys without holding the mutex, so that a slow UAA
+// doesn't block verifications with cached keys. Concurrent lookups of unknown
+// keys wait on the same fetch.
+func (v *Verifier) refreshKeys(ctx context.Context, fetch *keysFetch) {
+	keys, err := v.fetchKeys(ctx)
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if err != nil {
+		fetch.err = err
+	} else if len(keys) > 0 {
+		v.keys = keys
+	}
+
+	v.fetch = nil
+	close(fetch.done)
+}
+
+func (v *Verifier) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	tokenKeys, err := v.fetcher.TokenKeysWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrKeysUnavailable, err.Error())
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, tokenKey := range tokenKeys {
+		if tokenKey.Use != "" && tokenKey.Use != "sig" {
+			continue
+		}
+
+		publicKey, err := tokenKey.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[tokenKey.Kid] = publicKey
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,300 @@
+package uaa_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func signToken(key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"})
+	Expect(err).ToNot(HaveOccurred())
+	payload, err := json.Marshal(claims)
+	Expect(err).ToNot(HaveOccurred())
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	Expect(err).ToNot(HaveOccurred())
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func tokenKeysJSON(keys map[string]*rsa.PrivateKey) string {
+	tokenKeys := []uaa.TokenKey{}
+	for kid, key := range keys {
+		tokenKeys = append(tokenKeys, uaa.TokenKey{
+			Kid: kid,
+			Kty: "RSA",
+			Alg: "RS256",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	response, err := json.Marshal(map[string]interface{}{"keys": tokenKeys})
+	Expect(err).ToNot(HaveOccurred())
+	return string(response)
+}
+
+func validClaims(issuer string) map[string]interface{} {
+	return map[string]interface{}{
+		"user_name": "admin",
+		"scope":     []string{"cloud_controller.read"},
+		"aud":       []string{"cf", "cloud_controller"},
+		"iss":       issuer,
+		"iat":       time.Now().Unix(),
+		"exp":       time.Now().Add(10 * time.Minute).Unix(),
+	}
+}
+
+var _ = Describe("Verifier", func() {
+	var (
+		server      *httptest.Server
+		client      uaa.Client
+		verifier    *uaa.Verifier
+		keys        map[string]*rsa.PrivateKey
+		key         *rsa.PrivateKey
+		keyRequests int32
+		issuer      string
+	)
+
+	BeforeEach(func() {
+		var err error
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		keys = map[string]*rsa.PrivateKey{"key-1": key}
+		atomic.StoreInt32(&keyRequests, 0)
+
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/token_keys"))
+			atomic.AddInt32(&keyRequests, 1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(tokenKeysJSON(keys)))
+		}))
+		issuer = server.URL + "/oauth/token"
+		client = uaa.NewClientWithHTTPClient(server.URL, server.Client())
+		verifier = uaa.NewVerifier(&client, issuer, "cloud_controller")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("verifies the token and returns its claims", func() {
+		claims, err := verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(claims.UserName).To(Equal("admin"))
+		Expect(claims.HasScope("cloud_controller.read")).To(BeTrue())
+	})
+
+	It("caches the token keys", func() {
+		for i := 0; i < 3; i++ {
+			_, err := verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(atomic.LoadInt32(&keyRequests)).To(BeEquivalentTo(1))
+	})
+
+	It("refetches the keys when a new key id shows up", func() {
+		_, err := verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+		Expect(err).ToNot(HaveOccurred())
+
+		newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		keys = map[string]*rsa.PrivateKey{"key-2": newKey}
+		verifier.MinRefreshInterval = 0
+
+		_, err = verifier.Verify(signToken(newKey, "key-2", validClaims(issuer)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(atomic.LoadInt32(&keyRequests)).To(BeEquivalentTo(2))
+
+		_, err = verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+		Expect(err).To(Equal(uaa.ErrUnknownKey))
+	})
+
+	It("doesn't refetch the keys more often than the refresh interval", func() {
+		_, err := verifier.Verify(signToken(key, "unknown", validClaims(issuer)))
+		Expect(err).To(Equal(uaa.ErrUnknownKey))
+		_, err = verifier.Verify(signToken(key, "unknown", validClaims(issuer)))
+		Expect(err).To(Equal(uaa.ErrUnknownKey))
+
+		Expect(atomic.LoadInt32(&keyRequests)).To(BeEquivalentTo(1))
+	})
+
+	It("keeps the previous keys when the new ones are unusable", func() {
+		_, err := verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+		Expect(err).ToNot(HaveOccurred())
+
+		keys = map[string]*rsa.PrivateKey{}
+		verifier.MinRefreshInterval = 0
+
+		_, err = verifier.Verify(signToken(key, "key-2", validClaims(issuer)))
+		Expect(err).To(Equal(uaa.ErrUnknownKey))
+		Expect(atomic.LoadInt32(&keyRequests)).To(BeEquivalentTo(2))
+
+		_, err = verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("verifies tokens with known keys while fetching new ones", func() {
+		_, err := verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+		Expect(err).ToNot(HaveOccurred())
+
+		release := make(chan struct{})
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&keyRequests, 1)
+			<-release
+			w.Write([]byte(`{"keys":[]}`))
+		})
+		verifier.MinRefreshInterval = 0
+
+		fetched := make(chan error)
+		go func() {
+			_, err := verifier.Verify(signToken(key, "key-2", validClaims(issuer)))
+			fetched <- err
+		}()
+		Eventually(func() int32 { return atomic.LoadInt32(&keyRequests) }).Should(BeEquivalentTo(2))
+
+		_, err = verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+		Expect(err).ToNot(HaveOccurred())
+
+		close(release)
+		Eventually(fetched).Should(Receive(Equal(uaa.ErrUnknownKey)))
+	})
+
+	It("rejects tokens with an invalid signature", func() {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = verifier.Verify(signToken(otherKey, "key-1", validClaims(issuer)))
+		Expect(err).To(Equal(uaa.ErrInvalidSignature))
+	})
+
+	It("rejects tokens signed with other algorithms", func() {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"key-1"}`))
+		payload := base64.RawURLEncoding.EncodeToString([]byte(`{"user_name":"admin"}`))
+
+		_, err := verifier.Verify(header + "." + payload + ".")
+		Expect(err).To(MatchError("Unsupported token algorithm: none"))
+	})
+
+	It("rejects expired tokens", func() {
+		claims := validClaims(issuer)
+		claims["exp"] = time.Now().Add(-2 * time.Minute).Unix()
+
+		_, err := verifier.Verify(signToken(key, "key-1", claims))
+		Expect(err).To(Equal(uaa.ErrTokenExpired))
+	})
+
+	It("tolerates the configured clock skew", func() {
+		claims := validClaims(issuer)
+		claims["exp"] = time.Now().Add(-30 * time.Second).Unix()
+
+		_, err := verifier.Verify(signToken(key, "key-1", claims))
+		Expect(err).ToNot(HaveOccurred())
+
+		verifier.ClockSkew = 0
+		_, err = verifier.Verify(signToken(key, "key-1", claims))
+		Expect(err).To(Equal(uaa.ErrTokenExpired))
+	})
+
+	It("rejects tokens issued in the future", func() {
+		claims := validClaims(issuer)
+		claims["iat"] = time.Now().Add(5 * time.Minute).Unix()
+
+		_, err := verifier.Verify(signToken(key, "key-1", claims))
+		Expect(err).To(Equal(uaa.ErrTokenNotYetValid))
+	})
+
+	It("rejects tokens from other issuers", func() {
+		_, err := verifier.Verify(signToken(key, "key-1", validClaims("https://other.example.com/oauth/token")))
+		Expect(err).To(Equal(uaa.ErrInvalidIssuer))
+	})
+
+	It("rejects tokens for other audiences", func() {
+		claims := validClaims(issuer)
+		claims["aud"] = "cf"
+
+		_, err := verifier.Verify(signToken(key, "key-1", claims))
+		Expect(err).To(Equal(uaa.ErrInvalidAudience))
+	})
+
+	Context("when the token keys can't be fetched", func() {
+		BeforeEach(func() {
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			})
+		})
+
+		It("returns an error", func() {
+			_, err := verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+			Expect(err).To(MatchError(fmt.Sprintf("Failed to fetch token keys: UAA Error: %s (503)", http.StatusText(503))))
+			Expect(err).To(MatchError(uaa.ErrKeysUnavailable))
+		})
+	})
+
+	Context("when the token keys can't be fetched securely", func() {
+		It("refuses untrusted certificates", func() {
+			client = uaa.NewClient(server.URL)
+			verifier = uaa.NewVerifier(&client, issuer)
+
+			_, err := verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+			Expect(err).To(MatchError(uaa.ErrKeysUnavailable))
+			Expect(atomic.LoadInt32(&keyRequests)).To(BeEquivalentTo(0))
+		})
+
+		It("refuses clients that skip certificate verification", func() {
+			client = uaa.NewClientWithHTTPClient(server.URL, &http.Client{Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			}})
+			verifier = uaa.NewVerifier(&client, issuer)
+
+			_, err := verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+			Expect(err).To(MatchError("Failed to fetch token keys: Token keys can't be fetched without certificate verification"))
+		})
+
+		It("refuses plain http endpoints", func() {
+			client = uaa.NewClient(strings.Replace(server.URL, "https://", "http://", 1))
+			verifier = uaa.NewVerifier(&client, issuer)
+
+			_, err := verifier.Verify(signToken(key, "key-1", validClaims(issuer)))
+			Expect(err).To(MatchError("Failed to fetch token keys: Token keys must be fetched over https"))
+		})
+	})
+})
+
+var _ = Describe("TokenKey", func() {
+	It("parses PEM encoded keys", func() {
+		tokenKey := uaa.TokenKey{
+			Kid:   "legacy-token-key",
+			Value: "-----BEGIN PUBLIC KEY-----\nMFwwDQYJKoZIhvcNAQEBBQADSwAwSAJBAMQ0o8Zt3+P2N4Qhxq8rYwu4F9c7MEN2\nLxkTfRvN4Zy3mvP/YB9IU3jVqXYwRwNNpeHVzpuNlhKWu/5hRMZzT/0CAwEAAQ==\n-----END PUBLIC KEY-----",
+		}
+
+		publicKey, err := tokenKey.PublicKey()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(publicKey.E).To(Equal(65537))
+	})
+
+	It("rejects other key types", func() {
+		_, err := uaa.TokenKey{Kty: "EC"}.PublicKey()
+		Expect(err).To(MatchError("Unsupported key type: EC"))
+	})
+})
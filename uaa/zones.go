@@ -0,0 +1,164 @@
+package uaa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	ZoneIDHeader        = "X-Identity-Zone-Id"
+	ZoneSubdomainHeader = "X-Identity-Zone-Subdomain"
+)
+
+type IdentityZone struct {
+	ID           string          `json:"id,omitempty"`
+	Subdomain    string          `json:"subdomain"`
+	Name         string          `json:"name"`
+	Description  string          `json:"description,omitempty"`
+	Active       *bool           `json:"active,omitempty"`
+	Config       json.RawMessage `json:"config,omitempty"`
+	Version      int             `json:"version,omitempty"`
+	Created      int64           `json:"created,omitempty"`
+	LastModified int64           `json:"last_modified,omitempty"`
+}
+
+type zoneContextKey struct{}
+
+type zoneSelector struct {
+	id        string
+	subdomain string
+}
+
+func WithZoneID(ctx context.Context, zoneID string) context.Context {
+	return context.WithValue(ctx, zoneContextKey{}, zoneSelector{id: zoneID})
+}
+
+func WithZoneSubdomain(ctx context.Context, subdomain string) context.Context {
+	return context.WithValue(ctx, zoneContextKey{}, zoneSelector{subdomain: subdomain})
+}
+
+// InZone returns a copy of the client that talks to the zone's own
+// subdomain, e.g. https://my-zone.uaa.example.com.
+func (c *Client) InZone(subdomain string) (Client, error) {
+	endpoint, err := ZoneEndpoint(c.endpoint, subdomain)
+	if err != nil {
+		return Client{}, err
+	}
+
+	client := *c
+	client.endpoint = endpoint
+	client.ZoneID = ""
+	client.ZoneSubdomain = ""
+	return client, nil
+}
+
+func ZoneEndpoint(endpoint, subdomain string) (string, error) {
+	if subdomain == "" {
+		return endpoint, nil
+	}
+
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || endpointURL.Host == "" {
+		return "", fmt.Errorf("Invalid endpoint: %s", endpoint)
+	}
+
+	endpointURL.Host = subdomain + "." + endpointURL.Host
+	return strings.TrimSuffix(endpointURL.String(), "/"), nil
+}
+
+func (c *Client) zone(ctx context.Context) zoneSelector {
+	if selector, ok := ctx.Value(zoneContextKey{}).(zoneSelector); ok {
+		return selector
+	}
+
+	return zoneSelector{id: c.ZoneID, subdomain: c.ZoneSubdomain}
+}
+
+func (c *Client) setZoneHeaders(ctx context.Context, request *http.Request) {
+	selector := c.zone(ctx)
+	if selector.id != "" {
+		request.Header.Set(ZoneIDHeader, selector.id)
+	}
+	if selector.subdomain != "" {
+		request.Header.Set(ZoneSubdomainHeader, selector.subdomain)
+	}
+}
+
+func (c *Client) ListIdentityZones(accessToken string) ([]IdentityZone, error) {
+	return c.ListIdentityZonesWithContext(context.Background(), accessToken)
+}
+
+func (c *Client) ListIdentityZonesWithContext(ctx context.Context, accessToken string) ([]IdentityZone, error) {
+	var zones []IdentityZone
+	err := c.scimRequest(ctx, "GET", "/identity-zones", accessToken, &zones)
+	if err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+func (c *Client) GetIdentityZone(accessToken, zoneID string) (*IdentityZone, error) {
+	return c.GetIdentityZoneWithContext(context.Background(), accessToken, zoneID)
+}
+
+func (c *Client) GetIdentityZoneWithContext(ctx context.Context, accessToken, zoneID string) (*IdentityZone, error) {
+	if zoneID == "" {
+		return nil, errors.New("Missing zone id")
+	}
+
+	zone := new(IdentityZone)
+	err := c.scimRequest(ctx, "GET", "/identity-zones/"+url.PathEscape(zoneID), accessToken, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+func (c *Client) CreateIdentityZone(accessToken string, zone IdentityZone) (*IdentityZone, error) {
+	return c.CreateIdentityZoneWithContext(context.Background(), accessToken, zone)
+}
+
+func (c *Client) CreateIdentityZoneWithContext(ctx context.Context, accessToken string, zone IdentityZone) (*IdentityZone, error) {
+	return c.sendIdentityZone(ctx, "POST", "/identity-zones", accessToken, zone)
+}
+
+func (c *Client) UpdateIdentityZone(accessToken string, zone IdentityZone) (*IdentityZone, error) {
+	return c.UpdateIdentityZoneWithContext(context.Background(), accessToken, zone)
+}
+
+func (c *Client) UpdateIdentityZoneWithContext(ctx context.Context, accessToken string, zone IdentityZone) (*IdentityZone, error) {
+	if zone.ID == "" {
+		return nil, errors.New("Missing zone id")
+	}
+
+	return c.sendIdentityZone(ctx, "PUT", "/identity-zones/"+url.PathEscape(zone.ID), accessToken, zone)
+}
+
+func (c *Client) DeleteIdentityZone(accessToken, zoneID string) error {
+	return c.DeleteIdentityZoneWithContext(context.Background(), accessToken, zoneID)
+}
+
+func (c *Client) DeleteIdentityZoneWithContext(ctx context.Context, accessToken, zoneID string) error {
+	if zoneID == "" {
+		return errors.New("Missing zone id")
+	}
+
+	return c.scimRequest(ctx, "DELETE", "/identity-zones/"+url.PathEscape(zoneID), accessToken, nil)
+}
+
+func (c *Client) sendIdentityZone(ctx context.Context, method, path, accessToken string, zone IdentityZone) (*IdentityZone, error) {
+	result := new(IdentityZone)
+	err := c.sendScimJSON(ctx, method, path, accessToken, zone, "", result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
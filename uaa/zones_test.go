@@ -0,0 +1,173 @@
+package uaa_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tscolari/cfapi/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Identity zones", func() {
+	var (
+		server   *httptest.Server
+		handler  http.HandlerFunc
+		subject  uaa.Client
+		requests []*http.Request
+		bodies   []string
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = nil
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		}
+	})
+
+	JustBeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+
+			handler(w, r)
+		}))
+		subject = uaa.NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("zone headers", func() {
+		It("doesn't send zone headers by default", func() {
+			_, err := subject.GetUser("admin-token", "user-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests[0].Header).ToNot(HaveKey(uaa.ZoneIDHeader))
+			Expect(requests[0].Header).ToNot(HaveKey(uaa.ZoneSubdomainHeader))
+		})
+
+		It("sends the client zone on every request", func() {
+			subject.ZoneID = "tenant-zone"
+
+			_, err := subject.GetUser("admin-token", "user-guid")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = subject.AuthenticateWithClientCredentials()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests[0].Header.Get(uaa.ZoneIDHeader)).To(Equal("tenant-zone"))
+			Expect(requests[1].Header.Get(uaa.ZoneIDHeader)).To(Equal("tenant-zone"))
+		})
+
+		It("lets a call pick its own zone", func() {
+			subject.ZoneID = "tenant-zone"
+
+			ctx := uaa.WithZoneSubdomain(context.Background(), "other-tenant")
+			_, err := subject.GetUserWithContext(ctx, "admin-token", "user-guid")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests[0].Header.Get(uaa.ZoneSubdomainHeader)).To(Equal("other-tenant"))
+			Expect(requests[0].Header).ToNot(HaveKey(uaa.ZoneIDHeader))
+
+			ctx = uaa.WithZoneID(context.Background(), "third-zone")
+			_, err = subject.GetUserWithContext(ctx, "admin-token", "user-guid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests[1].Header.Get(uaa.ZoneIDHeader)).To(Equal("third-zone"))
+		})
+	})
+
+	Describe("ZoneEndpoint", func() {
+		It("prefixes the host with the subdomain", func() {
+			Expect(uaa.ZoneEndpoint("https://uaa.example.com", "tenant")).To(Equal("https://tenant.uaa.example.com"))
+			Expect(uaa.ZoneEndpoint("https://uaa.example.com:8443/", "tenant")).To(Equal("https://tenant.uaa.example.com:8443"))
+			Expect(uaa.ZoneEndpoint("https://uaa.example.com", "")).To(Equal("https://uaa.example.com"))
+		})
+
+		It("fails for invalid endpoints", func() {
+			_, err := uaa.ZoneEndpoint("uaa.example.com", "tenant")
+			Expect(err).To(MatchError("Invalid endpoint: uaa.example.com"))
+		})
+	})
+
+	Describe("InZone", func() {
+		It("returns a client for the zone subdomain", func() {
+			client := uaa.NewClient("https://uaa.example.com")
+			client.ZoneID = "tenant-zone"
+			client.ClientID = "admin"
+
+			zoneClient, err := client.InZone("tenant")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(zoneClient.ClientID).To(Equal("admin"))
+			Expect(zoneClient.ZoneID).To(BeEmpty())
+			Expect(client.ZoneID).To(Equal("tenant-zone"))
+		})
+	})
+
+	Describe("management", func() {
+		It("creates zones", func() {
+			_, err := subject.CreateIdentityZone("admin-token", uaa.IdentityZone{
+				ID:        "tenant-zone",
+				Subdomain: "tenant",
+				Name:      "Tenant",
+				Config:    []byte(`{"tokenPolicy":{"accessTokenValidity":3600}}`),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests[0].Method).To(Equal("POST"))
+			Expect(requests[0].URL.Path).To(Equal("/identity-zones"))
+			Expect(requests[0].Header.Get("Authorization")).To(Equal("bearer admin-token"))
+			Expect(bodies[0]).To(MatchJSON(`{"id":"tenant-zone","subdomain":"tenant","name":"Tenant","config":{"tokenPolicy":{"accessTokenValidity":3600}}}`))
+		})
+
+		It("updates zones", func() {
+			_, err := subject.UpdateIdentityZone("admin-token", uaa.IdentityZone{ID: "tenant-zone", Subdomain: "tenant", Name: "Renamed"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests[0].Method).To(Equal("PUT"))
+			Expect(requests[0].URL.Path).To(Equal("/identity-zones/tenant-zone"))
+		})
+
+		It("deletes zones", func() {
+			Expect(subject.DeleteIdentityZone("admin-token", "tenant-zone")).To(Succeed())
+			Expect(requests[0].Method).To(Equal("DELETE"))
+			Expect(requests[0].URL.Path).To(Equal("/identity-zones/tenant-zone"))
+		})
+
+		Context("when listing", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`[{"id":"uaa","subdomain":"","name":"uaa","active":true,"version":1},{"id":"tenant-zone","subdomain":"tenant","name":"Tenant"}]`))
+				}
+			})
+
+			It("lists the zones", func() {
+				zones, err := subject.ListIdentityZones("admin-token")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(zones).To(HaveLen(2))
+				Expect(zones[0].ID).To(Equal("uaa"))
+				Expect(*zones[0].Active).To(BeTrue())
+				Expect(zones[0].Version).To(Equal(1))
+				Expect(zones[1].Subdomain).To(Equal("tenant"))
+			})
+		})
+
+		Context("when the subdomain is taken", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusConflict)
+					w.Write([]byte(`{"error":"conflict","error_description":"The subdomain is taken"}`))
+				}
+			})
+
+			It("returns a conflict error", func() {
+				_, err := subject.CreateIdentityZone("admin-token", uaa.IdentityZone{Subdomain: "tenant"})
+				Expect(uaa.IsConflict(err)).To(BeTrue())
+			})
+		})
+	})
+})